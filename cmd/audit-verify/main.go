@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/1shoukr/swiftplay-backend/internal/audit"
+	"github.com/1shoukr/swiftplay-backend/internal/database"
+	"github.com/joho/godotenv"
+)
+
+// audit-verify walks the audit log and checks its hash chain. It exits with
+// status 1 when a record was altered, removed or inserted out of order.
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: .env file not found, using environment variables")
+	}
+
+	db, err := database.NewDatabase(database.LoadConfig())
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	result, err := audit.Verify(context.Background(), db.GetDB())
+	db.Close()
+	if err != nil {
+		log.Fatal("Failed to verify audit log:", err)
+	}
+
+	fmt.Println(result)
+	if result.BrokenAt != 0 {
+		os.Exit(1)
+	}
+}
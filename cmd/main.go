@@ -27,6 +27,17 @@ func main() {
 		}
 	}()
 
+	// SIGHUP reloads the JWT signing keys without a restart
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			if err := srv.JWTService.ReloadKeys(); err != nil {
+				log.Printf("Failed to reload JWT signing keys: %v", err)
+			}
+		}
+	}()
+
 	// Wait for interrupt signal to gracefully shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
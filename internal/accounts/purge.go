@@ -0,0 +1,124 @@
+package accounts
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// PurgeDeletedUsers permanently removes accounts that were soft-deleted before the
+// cutoff, together with everything that belongs to them. Reports and admin
+// actions are kept for the moderation history, with the account anonymised.
+func PurgeDeletedUsers(ctx context.Context, db *gorm.DB, cutoff time.Time) (int, error) {
+	var userIDs []uint
+	if err := db.WithContext(ctx).Unscoped().Model(&models.User{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Pluck("user_id", &userIDs).Error; err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, userID := range userIDs {
+		if err := purgeUser(db.WithContext(ctx), userID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+
+	if purged > 0 {
+		log.Printf("Purged %d deleted accounts", purged)
+	}
+	return purged, nil
+}
+
+// purgedUserID replaces a purged account's id in the records that outlive it,
+// such as reports and admin actions, so moderators keep the history without
+// it pointing back at the person
+const purgedUserID = 0
+
+func purgeUser(db *gorm.DB, userID uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var matchIDs []uint
+		if err := tx.Model(&models.Match{}).
+			Where("user_id_1 = ? OR user_id_2 = ?", userID, userID).
+			Pluck("match_id", &matchIDs).Error; err != nil {
+			return err
+		}
+
+		if len(matchIDs) > 0 {
+			if err := tx.Where("match_id IN ?", matchIDs).Delete(&models.Message{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("match_id IN ?", matchIDs).Delete(&models.MatchTransition{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("match_id IN ?", matchIDs).Delete(&models.Match{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("blocker_id = ? OR blocked_id = ?", userID, userID).Delete(&models.Block{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("swiper_id = ? OR target_id = ?", userID, userID).Delete(&models.Swipe{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? OR candidate_id = ?", userID, userID).Delete(&models.Recommendation{}).Error; err != nil {
+			return err
+		}
+		if err := anonymiseReports(tx, userID); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.AdminAction{}).Where("target_id = ?", userID).
+			Update("target_id", purgedUserID).Error; err != nil {
+			return err
+		}
+
+		owned := []interface{}{
+			&models.Profile{},
+			&models.RefreshToken{},
+			&models.AccountToken{},
+			&models.MFARecoveryCode{},
+			&models.UserMFA{},
+			&models.TokenCutoff{},
+		}
+		for _, model := range owned {
+			if err := tx.Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Unscoped().Delete(&models.User{}, userID).Error
+	})
+}
+
+// anonymiseReports keeps the reports the user filed or was the subject of, with
+// the user's id replaced in the report and in its evidence
+func anonymiseReports(tx *gorm.DB, userID uint) error {
+	var reports []models.Report
+	if err := tx.Where("reporter_id = ? OR reported_id = ?", userID, userID).Find(&reports).Error; err != nil {
+		return err
+	}
+
+	for i := range reports {
+		report := &reports[i]
+		if report.ReporterID == userID {
+			report.ReporterID = purgedUserID
+		}
+		if report.ReportedID == userID {
+			report.ReportedID = purgedUserID
+		}
+		for j := range report.Evidence {
+			if report.Evidence[j].SenderID == userID {
+				report.Evidence[j].SenderID = purgedUserID
+			}
+		}
+		if err := tx.Model(report).Select("reporter_id", "reported_id", "evidence").Updates(report).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
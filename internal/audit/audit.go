@@ -0,0 +1,124 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Audited events
+const (
+	EventLogin             = "auth.login"
+	EventLoginFailed       = "auth.login_failed"
+	EventTokenRefresh      = "auth.token_refresh"
+	EventPasswordChange    = "auth.password_change"
+	EventPasswordReset     = "auth.password_reset"
+	EventSuspend           = "admin.suspend"
+	EventBan               = "admin.ban"
+	EventReinstate         = "admin.reinstate"
+	EventRestore           = "admin.restore"
+	EventRoleChange        = "admin.role_change"
+	EventPrivilegedRequest = "admin.request"
+)
+
+// chainLockID is the Postgres advisory lock that serializes appends to the chain
+const chainLockID = 7_245_001
+
+// targetKey is the context key handlers use to name the account a request acts on
+const targetKey = "audit_target_id"
+
+// Entry is an event to be appended to the audit log. ActorID and TargetID are
+// zero when there is no such user.
+type Entry struct {
+	Event     string
+	ActorID   uint
+	TargetID  uint
+	IP        string
+	UserAgent string
+	Outcome   string
+	Details   map[string]interface{}
+}
+
+// Logger appends records to the hash-chained audit log
+type Logger struct {
+	db *gorm.DB
+}
+
+func NewLogger(db *gorm.DB) *Logger {
+	return &Logger{db: db}
+}
+
+// Record appends the entry to the chain. Appends take a transaction-scoped
+// advisory lock, so every record links to the one committed right before it
+// and record ids follow chain order.
+func (l *Logger) Record(ctx context.Context, entry Entry) error {
+	record := models.AuditRecord{
+		Event:     entry.Event,
+		ActorID:   optionalID(entry.ActorID),
+		TargetID:  optionalID(entry.TargetID),
+		IP:        entry.IP,
+		UserAgent: entry.UserAgent,
+		Outcome:   entry.Outcome,
+	}
+	if len(entry.Details) > 0 {
+		details, err := json.Marshal(entry.Details)
+		if err != nil {
+			return err
+		}
+		record.Details = string(details)
+	}
+
+	return l.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", chainLockID).Error; err != nil {
+			return err
+		}
+
+		var last []models.AuditRecord
+		if err := tx.Select("hash").Order("record_id DESC").Limit(1).Find(&last).Error; err != nil {
+			return err
+		}
+		if len(last) > 0 {
+			record.PrevHash = last[0].Hash
+		}
+
+		// Postgres keeps microseconds, so the stored time hashes the same way
+		record.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+		record.Hash = Hash(&record)
+		return tx.Create(&record).Error
+	})
+}
+
+// RecordRequest appends the entry with the client's IP and user agent. Failures
+// are logged rather than returned so that auditing never fails the request.
+func (l *Logger) RecordRequest(c *gin.Context, entry Entry) {
+	entry.IP = c.ClientIP()
+	entry.UserAgent = c.Request.UserAgent()
+	if err := l.Record(c.Request.Context(), entry); err != nil {
+		log.Printf("Failed to record audit event %s: %v", entry.Event, err)
+	}
+}
+
+// SetTarget names the account the request acts on, for the privileged request
+// record written once the request completes
+func SetTarget(c *gin.Context, userID uint) {
+	c.Set(targetKey, userID)
+}
+
+// Target returns the account set with SetTarget, or zero
+func Target(c *gin.Context) uint {
+	userID, _ := c.Get(targetKey)
+	id, _ := userID.(uint)
+	return id
+}
+
+func optionalID(id uint) *uint {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
@@ -0,0 +1,106 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// verifyBatchSize is how many records Verify loads at a time
+const verifyBatchSize = 1000
+
+// hashedRecord is the canonical form of a record that goes into its hash. The
+// record id is left out because it is only assigned on insert; chain order is
+// carried by prev_hash instead.
+type hashedRecord struct {
+	PrevHash  string `json:"prev_hash"`
+	Event     string `json:"event"`
+	ActorID   *uint  `json:"actor_id"`
+	TargetID  *uint  `json:"target_id"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+	Outcome   string `json:"outcome"`
+	Details   string `json:"details"`
+	CreatedAt string `json:"created_at"`
+}
+
+// Hash computes the SHA-256 hash of a record, chained to its prev_hash
+func Hash(record *models.AuditRecord) string {
+	payload, _ := json.Marshal(hashedRecord{
+		PrevHash:  record.PrevHash,
+		Event:     record.Event,
+		ActorID:   record.ActorID,
+		TargetID:  record.TargetID,
+		IP:        record.IP,
+		UserAgent: record.UserAgent,
+		Outcome:   record.Outcome,
+		Details:   record.Details,
+		CreatedAt: record.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// Result is the outcome of a chain verification
+type Result struct {
+	Checked int
+	// BrokenAt is the id of the first record that fails verification, or zero
+	// when the whole chain is intact
+	BrokenAt uint
+	Problem  string
+}
+
+// Verify walks the whole chain in record order, recomputing every hash and
+// checking that each record links to the one before it. Removing records from
+// the end of the chain cannot be detected this way; compare the latest hash
+// with a copy kept elsewhere for that.
+func Verify(ctx context.Context, db *gorm.DB) (*Result, error) {
+	result := &Result{}
+	prevHash := ""
+	var afterID uint
+
+	for {
+		var batch []models.AuditRecord
+		if err := db.WithContext(ctx).Where("record_id > ?", afterID).
+			Order("record_id").Limit(verifyBatchSize).Find(&batch).Error; err != nil {
+			return nil, err
+		}
+
+		for i := range batch {
+			record := &batch[i]
+			switch {
+			case record.PrevHash != prevHash:
+				result.BrokenAt = record.RecordID
+				result.Problem = "record does not link to the previous record"
+			case Hash(record) != record.Hash:
+				result.BrokenAt = record.RecordID
+				result.Problem = "record contents do not match its hash"
+			}
+			if result.BrokenAt != 0 {
+				return result, nil
+			}
+
+			prevHash = record.Hash
+			afterID = record.RecordID
+			result.Checked++
+		}
+
+		if len(batch) < verifyBatchSize {
+			return result, nil
+		}
+	}
+}
+
+// String describes the result for the verification command
+func (r *Result) String() string {
+	if r.BrokenAt != 0 {
+		return fmt.Sprintf("audit chain broken at record %d: %s (%d records verified before it)", r.BrokenAt, r.Problem, r.Checked)
+	}
+	return fmt.Sprintf("audit chain intact: %d records verified", r.Checked)
+}
@@ -0,0 +1,139 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"github.com/1shoukr/swiftplay-backend/internal/realtime"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MaxMessageLength is the longest message, in characters, a player can send
+const MaxMessageLength = 2000
+
+var (
+	ErrMatchNotFound  = errors.New("match not found")
+	ErrMatchNotActive = errors.New("messages are only available in accepted matches")
+	ErrInvalidContent = errors.New("message must be between 1 and 2000 characters")
+)
+
+// Service sends and reads chat messages and notifies the players of a match
+// over their live connections
+type Service struct {
+	db        *gorm.DB
+	publisher realtime.Publisher
+}
+
+func NewService(db *gorm.DB, publisher realtime.Publisher) *Service {
+	return &Service{db: db, publisher: publisher}
+}
+
+// ActiveMatch returns the match if userID is one of its players and it is accepted
+func (s *Service) ActiveMatch(ctx context.Context, matchID, userID uint) (*models.Match, error) {
+	return activeMatch(s.db.WithContext(ctx), matchID, userID)
+}
+
+// activeMatch is ActiveMatch on the given connection, which may add a row lock
+func activeMatch(db *gorm.DB, matchID, userID uint) (*models.Match, error) {
+	var matches []models.Match
+	if err := db.Where("match_id = ?", matchID).Limit(1).Find(&matches).Error; err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 || !matches[0].HasParticipant(userID) {
+		return nil, ErrMatchNotFound
+	}
+	if matches[0].Status != models.MatchStatusAccepted {
+		return nil, ErrMatchNotActive
+	}
+	return &matches[0], nil
+}
+
+// Send stores a message from senderID in the match and delivers it to both
+// players, so the sender's other devices see it too
+func (s *Service) Send(ctx context.Context, matchID, senderID uint, content string) (*models.Message, error) {
+	content = strings.TrimSpace(content)
+	if content == "" || utf8.RuneCountInString(content) > MaxMessageLength {
+		return nil, ErrInvalidContent
+	}
+
+	// The match stays locked until the message is stored, so a block or unmatch
+	// either lands first and rejects the message or waits for it
+	var match *models.Match
+	var message models.Message
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		match, err = activeMatch(tx.Clauses(clause.Locking{Strength: "UPDATE"}), matchID, senderID)
+		if err != nil {
+			return err
+		}
+
+		message = models.Message{
+			MatchID:  match.MatchID,
+			SenderID: senderID,
+			Content:  content,
+		}
+		return tx.Create(&message).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publisher.Publish([]uint{match.UserID1, match.UserID2}, realtime.Event{
+		Type:    realtime.EventMessageCreated,
+		MatchID: match.MatchID,
+		UserID:  senderID,
+		Data:    message,
+	})
+	return &message, nil
+}
+
+// MarkRead sets read_at on every unread message userID received in the match,
+// up to and including upToMessageID, and sends a read receipt to both players.
+// It returns the number of messages marked and the time they were read.
+func (s *Service) MarkRead(ctx context.Context, matchID, userID, upToMessageID uint) (int64, time.Time, error) {
+	match, err := s.ActiveMatch(ctx, matchID, userID)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	readAt := time.Now()
+	result := s.db.WithContext(ctx).Model(&models.Message{}).
+		Where("match_id = ? AND sender_id <> ? AND read_at IS NULL AND message_id <= ?", match.MatchID, userID, upToMessageID).
+		Update("read_at", readAt)
+	if result.Error != nil {
+		return 0, time.Time{}, result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		s.publisher.Publish([]uint{match.UserID1, match.UserID2}, realtime.Event{
+			Type:    realtime.EventMessagesRead,
+			MatchID: match.MatchID,
+			UserID:  userID,
+			Data: map[string]interface{}{
+				"up_to_message_id": upToMessageID,
+				"read_at":          readAt,
+			},
+		})
+	}
+	return result.RowsAffected, readAt, nil
+}
+
+// Typing tells the other player of the match that userID is typing
+func (s *Service) Typing(ctx context.Context, matchID, userID uint) error {
+	match, err := s.ActiveMatch(ctx, matchID, userID)
+	if err != nil {
+		return err
+	}
+
+	s.publisher.Publish([]uint{match.OtherUser(userID)}, realtime.Event{
+		Type:    realtime.EventTyping,
+		MatchID: match.MatchID,
+		UserID:  userID,
+	})
+	return nil
+}
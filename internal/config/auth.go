@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuthConfig holds account lifecycle and authorization settings
+type AuthConfig struct {
+	AppBaseURL              string
+	EmailVerificationExpiry time.Duration
+	PasswordResetExpiry     time.Duration
+	MFAIssuer               string
+	MFARequiredForElevated  bool
+	RBACPolicyPath          string
+	LiveUserLookup          bool
+	UserStateCacheTTL       time.Duration
+	DeletionGracePeriod     time.Duration
+	PurgeInterval           time.Duration
+}
+
+// LoadAuthConfig loads account lifecycle configuration from environment variables
+func LoadAuthConfig() (*AuthConfig, error) {
+	verificationExpiry, err := time.ParseDuration(getEnv("EMAIL_VERIFICATION_EXPIRY", "48h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EMAIL_VERIFICATION_EXPIRY format: %w", err)
+	}
+
+	resetExpiry, err := time.ParseDuration(getEnv("PASSWORD_RESET_EXPIRY", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PASSWORD_RESET_EXPIRY format: %w", err)
+	}
+
+	mfaRequired, err := strconv.ParseBool(getEnv("MFA_REQUIRED_FOR_ELEVATED_ROLES", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MFA_REQUIRED_FOR_ELEVATED_ROLES value: %w", err)
+	}
+
+	liveUserLookup, err := strconv.ParseBool(getEnv("AUTH_LIVE_USER_LOOKUP", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AUTH_LIVE_USER_LOOKUP value: %w", err)
+	}
+
+	userStateCacheTTL, err := time.ParseDuration(getEnv("AUTH_USER_CACHE_TTL", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AUTH_USER_CACHE_TTL format: %w", err)
+	}
+
+	deletionGracePeriod, err := time.ParseDuration(getEnv("ACCOUNT_DELETION_GRACE_PERIOD", "720h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ACCOUNT_DELETION_GRACE_PERIOD format: %w", err)
+	}
+
+	purgeInterval, err := time.ParseDuration(getEnv("ACCOUNT_PURGE_INTERVAL", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ACCOUNT_PURGE_INTERVAL format: %w", err)
+	}
+
+	return &AuthConfig{
+		AppBaseURL:              strings.TrimRight(getEnv("APP_BASE_URL", "http://localhost:8081"), "/"),
+		EmailVerificationExpiry: verificationExpiry,
+		PasswordResetExpiry:     resetExpiry,
+		MFAIssuer:               getEnv("MFA_ISSUER", "SwiftPlay"),
+		MFARequiredForElevated:  mfaRequired,
+		RBACPolicyPath:          getEnv("RBAC_POLICY_PATH", ""),
+		LiveUserLookup:          liveUserLookup,
+		UserStateCacheTTL:       userStateCacheTTL,
+		DeletionGracePeriod:     deletionGracePeriod,
+		PurgeInterval:           purgeInterval,
+	}, nil
+}
@@ -12,6 +12,22 @@ type JWTConfig struct {
 	Expiry             time.Duration
 	RefreshTokenSecret string
 	RefreshTokenExpiry time.Duration
+	RevocationStore    string
+	MFAPendingExpiry   time.Duration
+	KeysDir            string
+	ActiveKeyID        string
+	KeysReloadInterval time.Duration
+	HMACFallbackUntil  time.Time
+	Issuer             string
+	MobileAudience     string
+	AdminAudience      string
+	InternalAudience   string
+	Leeway             time.Duration
+}
+
+// Audiences lists every audience tokens may be issued for
+func (c *JWTConfig) Audiences() []string {
+	return []string{c.MobileAudience, c.AdminAudience, c.InternalAudience}
 }
 
 // LoadJWTConfig loads JWT configuration from environment variables
@@ -46,11 +62,61 @@ func LoadJWTConfig() (*JWTConfig, error) {
 		return nil, fmt.Errorf("invalid REFRESH_TOKEN_EXPIRY format: %w", err)
 	}
 
+	mfaPendingExpiry, err := time.ParseDuration(getEnv("MFA_PENDING_TOKEN_EXPIRY", "5m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MFA_PENDING_TOKEN_EXPIRY format: %w", err)
+	}
+
+	keysReloadInterval, err := time.ParseDuration(getEnv("JWT_KEYS_RELOAD_INTERVAL", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_KEYS_RELOAD_INTERVAL format: %w", err)
+	}
+
+	var hmacFallbackUntil time.Time
+	if value := getEnv("JWT_HMAC_FALLBACK_UNTIL", ""); value != "" {
+		hmacFallbackUntil, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT_HMAC_FALLBACK_UNTIL format: %w", err)
+		}
+	}
+
+	leeway, err := time.ParseDuration(getEnv("JWT_LEEWAY", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_LEEWAY format: %w", err)
+	}
+
+	if leeway < 0 || leeway > 5*time.Minute {
+		return nil, fmt.Errorf("JWT_LEEWAY must be between 0s and 5m")
+	}
+
+	mobileAudience := getEnv("JWT_AUDIENCE_MOBILE", "swiftplay-mobile")
+	adminAudience := getEnv("JWT_AUDIENCE_ADMIN", "swiftplay-admin")
+	internalAudience := getEnv("JWT_AUDIENCE_INTERNAL", "swiftplay-internal")
+	if mobileAudience == adminAudience || mobileAudience == internalAudience || adminAudience == internalAudience {
+		return nil, fmt.Errorf("JWT_AUDIENCE_MOBILE, JWT_AUDIENCE_ADMIN and JWT_AUDIENCE_INTERNAL must be distinct")
+	}
+
+	revocationStore := getEnv("TOKEN_REVOCATION_STORE", "postgres")
+	if revocationStore != "memory" && revocationStore != "postgres" {
+		return nil, fmt.Errorf("invalid TOKEN_REVOCATION_STORE value: %s (must be memory or postgres)", revocationStore)
+	}
+
 	return &JWTConfig{
 		Secret:             secret,
 		Expiry:             expiry,
 		RefreshTokenSecret: refreshSecret,
 		RefreshTokenExpiry: refreshExpiry,
+		RevocationStore:    revocationStore,
+		MFAPendingExpiry:   mfaPendingExpiry,
+		KeysDir:            getEnv("JWT_KEYS_DIR", ""),
+		ActiveKeyID:        getEnv("JWT_ACTIVE_KID", ""),
+		KeysReloadInterval: keysReloadInterval,
+		HMACFallbackUntil:  hmacFallbackUntil,
+		Issuer:             getEnv("JWT_ISSUER", "swiftplay-backend"),
+		MobileAudience:     mobileAudience,
+		AdminAudience:      adminAudience,
+		InternalAudience:   internalAudience,
+		Leeway:             leeway,
 	}, nil
 }
 
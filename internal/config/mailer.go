@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// MailerConfig holds outgoing email configuration
+type MailerConfig struct {
+	Driver       string
+	From         string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	LogPath      string
+}
+
+// LoadMailerConfig loads mailer configuration from environment variables
+func LoadMailerConfig() (*MailerConfig, error) {
+	driver := getEnv("MAILER_DRIVER", "log")
+	if driver != "log" && driver != "smtp" {
+		return nil, fmt.Errorf("invalid MAILER_DRIVER value: %s (must be log or smtp)", driver)
+	}
+
+	// The log mailer writes verification and password reset links in full, so
+	// release builds only use it when it was asked for
+	if os.Getenv("MAILER_DRIVER") == "" && getEnv("GIN_MODE", "debug") == "release" {
+		return nil, fmt.Errorf("MAILER_DRIVER must be set when GIN_MODE is release")
+	}
+
+	portStr := getEnv("SMTP_PORT", "587")
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP_PORT value: %w", err)
+	}
+
+	cfg := &MailerConfig{
+		Driver:       driver,
+		From:         getEnv("MAIL_FROM", "SwiftPlay <no-reply@swiftplay.local>"),
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     port,
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		LogPath:      getEnv("MAIL_LOG_PATH", ""),
+	}
+
+	if driver == "smtp" && cfg.SMTPHost == "" {
+		return nil, fmt.Errorf("SMTP_HOST is required when MAILER_DRIVER is smtp")
+	}
+
+	return cfg, nil
+}
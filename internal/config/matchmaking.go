@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// MatchmakingConfig holds settings for player discovery and matching
+type MatchmakingConfig struct {
+	GameCatalogPath string
+	PassCooldown    time.Duration
+	PendingMatchTTL time.Duration
+	ExpiryInterval  time.Duration
+}
+
+// LoadMatchmakingConfig loads matchmaking configuration from environment variables
+func LoadMatchmakingConfig() (*MatchmakingConfig, error) {
+	passCooldown, err := time.ParseDuration(getEnv("PASS_COOLDOWN", "336h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PASS_COOLDOWN format: %w", err)
+	}
+
+	pendingMatchTTL, err := time.ParseDuration(getEnv("PENDING_MATCH_TTL", "168h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PENDING_MATCH_TTL format: %w", err)
+	}
+
+	expiryInterval, err := time.ParseDuration(getEnv("MATCH_EXPIRY_INTERVAL", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MATCH_EXPIRY_INTERVAL format: %w", err)
+	}
+
+	return &MatchmakingConfig{
+		GameCatalogPath: getEnv("GAME_CATALOG_PATH", ""),
+		PassCooldown:    passCooldown,
+		PendingMatchTTL: pendingMatchTTL,
+		ExpiryInterval:  expiryInterval,
+	}, nil
+}
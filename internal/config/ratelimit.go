@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// RateLimitConfig holds request rate limits and login lockout settings. Limits
+// are requests per minute; zero disables a limit.
+type RateLimitConfig struct {
+	Store            string
+	Default          int
+	Auth             int
+	Refresh          int
+	Messages         int
+	PruneInterval    time.Duration
+	LockoutThreshold int
+	LockoutBaseDelay time.Duration
+	LockoutMaxDelay  time.Duration
+	LockoutWindow    time.Duration
+}
+
+// LoadRateLimitConfig loads rate limiting configuration from environment variables
+func LoadRateLimitConfig() (*RateLimitConfig, error) {
+	store := getEnv("RATE_LIMIT_STORE", "memory")
+	if store != "memory" && store != "postgres" {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_STORE value: %s (must be memory or postgres)", store)
+	}
+
+	defaultLimit, err := strconv.Atoi(getEnv("RATE_LIMIT", "100"))
+	if err != nil || defaultLimit < 0 {
+		return nil, fmt.Errorf("invalid RATE_LIMIT value: must be a non-negative integer")
+	}
+
+	authLimit, err := strconv.Atoi(getEnv("RATE_LIMIT_AUTH", "20"))
+	if err != nil || authLimit < 0 {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_AUTH value: must be a non-negative integer")
+	}
+
+	refreshLimit, err := strconv.Atoi(getEnv("RATE_LIMIT_REFRESH", "60"))
+	if err != nil || refreshLimit < 0 {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_REFRESH value: must be a non-negative integer")
+	}
+
+	messageLimit, err := strconv.Atoi(getEnv("RATE_LIMIT_MESSAGES", "30"))
+	if err != nil || messageLimit < 0 {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_MESSAGES value: must be a non-negative integer")
+	}
+
+	pruneInterval, err := time.ParseDuration(getEnv("RATE_LIMIT_PRUNE_INTERVAL", "10m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_PRUNE_INTERVAL format: %w", err)
+	}
+
+	lockoutThreshold, err := strconv.Atoi(getEnv("LOGIN_LOCKOUT_THRESHOLD", "5"))
+	if err != nil || lockoutThreshold < 0 {
+		return nil, fmt.Errorf("invalid LOGIN_LOCKOUT_THRESHOLD value: must be a non-negative integer")
+	}
+
+	lockoutBaseDelay, err := time.ParseDuration(getEnv("LOGIN_LOCKOUT_BASE_DELAY", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOGIN_LOCKOUT_BASE_DELAY format: %w", err)
+	}
+
+	lockoutMaxDelay, err := time.ParseDuration(getEnv("LOGIN_LOCKOUT_MAX_DELAY", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOGIN_LOCKOUT_MAX_DELAY format: %w", err)
+	}
+	if lockoutMaxDelay <= 0 {
+		return nil, fmt.Errorf("invalid LOGIN_LOCKOUT_MAX_DELAY value: must be a positive duration")
+	}
+
+	lockoutWindow, err := time.ParseDuration(getEnv("LOGIN_LOCKOUT_WINDOW", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOGIN_LOCKOUT_WINDOW format: %w", err)
+	}
+
+	return &RateLimitConfig{
+		Store:            store,
+		Default:          defaultLimit,
+		Auth:             authLimit,
+		Refresh:          refreshLimit,
+		Messages:         messageLimit,
+		PruneInterval:    pruneInterval,
+		LockoutThreshold: lockoutThreshold,
+		LockoutBaseDelay: lockoutBaseDelay,
+		LockoutMaxDelay:  lockoutMaxDelay,
+		LockoutWindow:    lockoutWindow,
+	}, nil
+}
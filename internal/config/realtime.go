@@ -0,0 +1,41 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// RealtimeConfig holds settings for the live event stream
+type RealtimeConfig struct {
+	StreamLogSize      int
+	StreamLogRetention time.Duration
+	StreamKeepAlive    time.Duration
+}
+
+// LoadRealtimeConfig loads live event stream configuration from environment variables
+func LoadRealtimeConfig() (*RealtimeConfig, error) {
+	logSize, err := strconv.Atoi(getEnv("STREAM_LOG_SIZE", "200"))
+	if err != nil || logSize < 1 {
+		return nil, fmt.Errorf("invalid STREAM_LOG_SIZE value: must be a positive integer")
+	}
+
+	logRetention, err := time.ParseDuration(getEnv("STREAM_LOG_RETENTION", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid STREAM_LOG_RETENTION format: %w", err)
+	}
+
+	keepAlive, err := time.ParseDuration(getEnv("STREAM_KEEPALIVE", "25s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid STREAM_KEEPALIVE format: %w", err)
+	}
+	if keepAlive <= 0 {
+		return nil, fmt.Errorf("invalid STREAM_KEEPALIVE value: must be a positive duration")
+	}
+
+	return &RealtimeConfig{
+		StreamLogSize:      logSize,
+		StreamLogRetention: logRetention,
+		StreamKeepAlive:    keepAlive,
+	}, nil
+}
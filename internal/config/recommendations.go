@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/scoring"
+)
+
+// RecommendationConfig holds settings for the recommendation feed
+type RecommendationConfig struct {
+	Weights          scoring.Weights
+	MaxDistanceKm    float64
+	MaxAgeGap        int
+	ActivityHalfLife time.Duration
+	CandidatePool    int
+	FeedSize         int
+	CacheTTL         time.Duration
+	RefreshInterval  time.Duration
+	ActiveWithin     time.Duration
+}
+
+// LoadRecommendationConfig loads recommendation configuration from environment variables
+func LoadRecommendationConfig() (*RecommendationConfig, error) {
+	weights := scoring.DefaultWeights()
+	weightVars := []struct {
+		key    string
+		weight *float64
+	}{
+		{"RECOMMENDATION_WEIGHT_RANK", &weights.RankProximity},
+		{"RECOMMENDATION_WEIGHT_SHARED_GAMES", &weights.SharedGames},
+		{"RECOMMENDATION_WEIGHT_DISTANCE", &weights.Distance},
+		{"RECOMMENDATION_WEIGHT_AGE", &weights.Age},
+		{"RECOMMENDATION_WEIGHT_AVAILABILITY", &weights.Availability},
+		{"RECOMMENDATION_WEIGHT_ACTIVITY", &weights.Activity},
+	}
+	for _, v := range weightVars {
+		value, err := strconv.ParseFloat(getEnv(v.key, strconv.FormatFloat(*v.weight, 'f', -1, 64)), 64)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("invalid %s value: must be a non-negative number", v.key)
+		}
+		*v.weight = value
+	}
+
+	maxDistanceKm, err := strconv.ParseFloat(getEnv("RECOMMENDATION_MAX_DISTANCE_KM", "200"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RECOMMENDATION_MAX_DISTANCE_KM value: %w", err)
+	}
+	if maxDistanceKm < 0 {
+		return nil, fmt.Errorf("invalid RECOMMENDATION_MAX_DISTANCE_KM value: must be a non-negative number")
+	}
+
+	maxAgeGap, err := strconv.Atoi(getEnv("RECOMMENDATION_MAX_AGE_GAP", "10"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RECOMMENDATION_MAX_AGE_GAP value: %w", err)
+	}
+	if maxAgeGap < 0 {
+		return nil, fmt.Errorf("invalid RECOMMENDATION_MAX_AGE_GAP value: must be a non-negative integer")
+	}
+
+	activityHalfLife, err := time.ParseDuration(getEnv("RECOMMENDATION_ACTIVITY_HALF_LIFE", "72h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RECOMMENDATION_ACTIVITY_HALF_LIFE format: %w", err)
+	}
+
+	candidatePool, err := strconv.Atoi(getEnv("RECOMMENDATION_CANDIDATE_POOL", "500"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RECOMMENDATION_CANDIDATE_POOL value: %w", err)
+	}
+	if candidatePool < 1 {
+		return nil, fmt.Errorf("invalid RECOMMENDATION_CANDIDATE_POOL value: must be a positive integer")
+	}
+
+	feedSize, err := strconv.Atoi(getEnv("RECOMMENDATION_FEED_SIZE", "100"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RECOMMENDATION_FEED_SIZE value: %w", err)
+	}
+	if feedSize < 1 {
+		return nil, fmt.Errorf("invalid RECOMMENDATION_FEED_SIZE value: must be a positive integer")
+	}
+
+	cacheTTL, err := time.ParseDuration(getEnv("RECOMMENDATION_CACHE_TTL", "6h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RECOMMENDATION_CACHE_TTL format: %w", err)
+	}
+
+	refreshInterval, err := time.ParseDuration(getEnv("RECOMMENDATION_REFRESH_INTERVAL", "30m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RECOMMENDATION_REFRESH_INTERVAL format: %w", err)
+	}
+
+	activeWithin, err := time.ParseDuration(getEnv("RECOMMENDATION_ACTIVE_WITHIN", "168h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RECOMMENDATION_ACTIVE_WITHIN format: %w", err)
+	}
+
+	return &RecommendationConfig{
+		Weights:          weights,
+		MaxDistanceKm:    maxDistanceKm,
+		MaxAgeGap:        maxAgeGap,
+		ActivityHalfLife: activityHalfLife,
+		CandidatePool:    candidatePool,
+		FeedSize:         feedSize,
+		CacheTTL:         cacheTTL,
+		RefreshInterval:  refreshInterval,
+		ActiveWithin:     activeWithin,
+	}, nil
+}
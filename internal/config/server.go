@@ -10,10 +10,16 @@ import (
 
 // ServerConfig holds all server configuration
 type ServerConfig struct {
-	Port    int
-	GinMode string
-	DB      *database.Config
-	JWT     *JWTConfig
+	Port            int
+	GinMode         string
+	DB              *database.Config
+	JWT             *JWTConfig
+	Auth            *AuthConfig
+	Mailer          *MailerConfig
+	Matchmaking     *MatchmakingConfig
+	Recommendations *RecommendationConfig
+	Realtime        *RealtimeConfig
+	RateLimit       *RateLimitConfig
 }
 
 // LoadServerConfig loads all configuration from environment variables
@@ -23,6 +29,36 @@ func LoadServerConfig() (*ServerConfig, error) {
 		return nil, fmt.Errorf("failed to load JWT configuration: %w", err)
 	}
 
+	authConfig, err := LoadAuthConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load auth configuration: %w", err)
+	}
+
+	mailerConfig, err := LoadMailerConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mailer configuration: %w", err)
+	}
+
+	matchmakingConfig, err := LoadMatchmakingConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load matchmaking configuration: %w", err)
+	}
+
+	recommendationConfig, err := LoadRecommendationConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recommendation configuration: %w", err)
+	}
+
+	realtimeConfig, err := LoadRealtimeConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load realtime configuration: %w", err)
+	}
+
+	rateLimitConfig, err := LoadRateLimitConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rate limit configuration: %w", err)
+	}
+
 	dbConfig := database.LoadConfig()
 
 	portStr := getEnv("PORT", "8081")
@@ -37,10 +73,16 @@ func LoadServerConfig() (*ServerConfig, error) {
 	}
 
 	return &ServerConfig{
-		Port:    port,
-		GinMode: ginMode,
-		DB:      dbConfig,
-		JWT:     jwtConfig,
+		Port:            port,
+		GinMode:         ginMode,
+		DB:              dbConfig,
+		JWT:             jwtConfig,
+		Auth:            authConfig,
+		Mailer:          mailerConfig,
+		Matchmaking:     matchmakingConfig,
+		Recommendations: recommendationConfig,
+		Realtime:        realtimeConfig,
+		RateLimit:       rateLimitConfig,
 	}, nil
 }
 
@@ -59,7 +59,7 @@ func NewDatabase(config *Config) (*Database, error) {
 	log.Println("Connected to PostgreSQL database successfully with GORM")
 
 	// Auto-migrate the schema
-	if err := conn.AutoMigrate(&models.User{}, &models.Profile{}, &models.Match{}, &models.Message{}); err != nil {
+	if err := conn.AutoMigrate(&models.User{}, &models.Profile{}, &models.Match{}, &models.Message{}, &models.RefreshToken{}, &models.RevokedToken{}, &models.TokenCutoff{}, &models.AccountToken{}, &models.UserMFA{}, &models.MFARecoveryCode{}, &models.Block{}, &models.Swipe{}, &models.MatchTransition{}, &models.Recommendation{}, &models.Report{}, &models.AdminAction{}, &models.AuditRecord{}, &models.RateLimitBucket{}, &models.LoginFailure{}); err != nil {
 		return nil, fmt.Errorf("failed to auto-migrate schema: %w", err)
 	}
 
@@ -0,0 +1,255 @@
+package games
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrUnknownGame = errors.New("game is not supported")
+	ErrUnknownRank = errors.New("rank is not valid for this game")
+)
+
+//go:embed catalog.json
+var defaultCatalog []byte
+
+// tierDefinition is a rank tier as written in the catalog file. Tiers are listed
+// from lowest to highest and expand into one rank per division.
+type tierDefinition struct {
+	Name         string `json:"name"`
+	Abbreviation string `json:"abbreviation"`
+	Divisions    int    `json:"divisions"`
+	// Descending marks ladders where division 1 is the highest within the tier
+	Descending bool `json:"descending"`
+}
+
+// gameDefinition is a game as written in the catalog file
+type gameDefinition struct {
+	ID      string           `json:"id"`
+	Name    string           `json:"name"`
+	Aliases []string         `json:"aliases"`
+	Regions []string         `json:"regions"`
+	Tiers   []tierDefinition `json:"tiers"`
+}
+
+// Rank is a single step on a game's ladder. Value increases with skill and is
+// comparable between ranks of the same game.
+type Rank struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+}
+
+// Game is a supported game with its ordered rank ladder and regions
+type Game struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+	Regions []string `json:"regions"`
+	Ranks   []Rank   `json:"ranks"`
+
+	rankKeys map[string]int
+}
+
+// Catalog is the set of supported games
+type Catalog struct {
+	games  []*Game
+	byKeys map[string]*Game
+}
+
+// Load reads a catalog file, or the bundled default catalog when path is empty
+func Load(path string) (*Catalog, error) {
+	contents := defaultCatalog
+	if path != "" {
+		var err error
+		contents, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read game catalog: %w", err)
+		}
+	}
+
+	var file struct {
+		Games []gameDefinition `json:"games"`
+	}
+	if err := json.Unmarshal(contents, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse game catalog: %w", err)
+	}
+
+	return newCatalog(file.Games)
+}
+
+func newCatalog(definitions []gameDefinition) (*Catalog, error) {
+	if len(definitions) == 0 {
+		return nil, fmt.Errorf("game catalog defines no games")
+	}
+
+	c := &Catalog{byKeys: make(map[string]*Game)}
+	for i, def := range definitions {
+		if def.ID == "" || def.Name == "" {
+			return nil, fmt.Errorf("game catalog entry %d needs an id and a name", i)
+		}
+
+		game, err := newGame(def)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range append([]string{def.ID, def.Name}, def.Aliases...) {
+			key := normalize(name)
+			if existing, ok := c.byKeys[key]; ok && existing != game {
+				return nil, fmt.Errorf("game catalog name %q is used by both %q and %q", name, existing.ID, game.ID)
+			}
+			c.byKeys[key] = game
+		}
+		c.games = append(c.games, game)
+	}
+
+	return c, nil
+}
+
+// newGame expands the tier definitions into the game's ladder and lookup keys
+func newGame(def gameDefinition) (*Game, error) {
+	if len(def.Tiers) == 0 {
+		return nil, fmt.Errorf("game %q defines no rank tiers", def.ID)
+	}
+
+	game := &Game{
+		ID:       def.ID,
+		Name:     def.Name,
+		Aliases:  def.Aliases,
+		Regions:  def.Regions,
+		rankKeys: make(map[string]int),
+	}
+	if game.Regions == nil {
+		game.Regions = []string{}
+	}
+
+	addKey := func(key string, index int) error {
+		if existing, ok := game.rankKeys[key]; ok && existing != index {
+			return fmt.Errorf("game %q has ambiguous ranks %q and %q", def.ID, game.Ranks[existing].Name, game.Ranks[index].Name)
+		}
+		game.rankKeys[key] = index
+		return nil
+	}
+
+	for _, tier := range def.Tiers {
+		if tier.Name == "" {
+			return nil, fmt.Errorf("game %q has a rank tier without a name", def.ID)
+		}
+
+		if tier.Divisions <= 1 {
+			index := len(game.Ranks)
+			game.Ranks = append(game.Ranks, Rank{Name: tier.Name, Value: index + 1})
+			for _, name := range []string{tier.Name, tier.Abbreviation} {
+				if name == "" {
+					continue
+				}
+				if err := addKey(normalize(name), index); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		for step := 1; step <= tier.Divisions; step++ {
+			division := step
+			if tier.Descending {
+				division = tier.Divisions - step + 1
+			}
+
+			index := len(game.Ranks)
+			game.Ranks = append(game.Ranks, Rank{
+				Name:  fmt.Sprintf("%s %d", tier.Name, division),
+				Value: index + 1,
+			})
+			for _, name := range []string{tier.Name, tier.Abbreviation} {
+				if name == "" {
+					continue
+				}
+				if err := addKey(normalize(name)+strconv.Itoa(division), index); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return game, nil
+}
+
+// Games lists the supported games in catalog order
+func (c *Catalog) Games() []*Game {
+	return append([]*Game{}, c.games...)
+}
+
+// Game finds a game by its id, display name or one of its aliases
+func (c *Catalog) Game(name string) (*Game, bool) {
+	game, ok := c.byKeys[normalize(name)]
+	return game, ok
+}
+
+// ResolveRank maps a free-form game and rank to the catalog's game and ladder
+// step, accepting spellings such as "Diamond 2", "diamond2", "D2" or "Diamond II"
+func (c *Catalog) ResolveRank(gameName, rankName string) (*Game, Rank, error) {
+	game, ok := c.Game(gameName)
+	if !ok {
+		return nil, Rank{}, ErrUnknownGame
+	}
+
+	rank, ok := game.Rank(rankName)
+	if !ok {
+		return game, Rank{}, ErrUnknownRank
+	}
+	return game, rank, nil
+}
+
+// Rank finds a step on the game's ladder from any accepted spelling
+func (g *Game) Rank(name string) (Rank, bool) {
+	index, ok := g.rankKeys[normalizeRank(name)]
+	if !ok {
+		return Rank{}, false
+	}
+	return g.Ranks[index], true
+}
+
+// HasRegion reports whether the game is played in the region
+func (g *Game) HasRegion(region string) bool {
+	for _, r := range g.Regions {
+		if strings.EqualFold(r, region) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize lower-cases the name and drops everything but letters and digits
+func normalize(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+var romanDivisions = map[string]string{
+	"i": "1", "ii": "2", "iii": "3", "iv": "4", "v": "5", "vi": "6",
+}
+
+// normalizeRank is normalize with a trailing roman numeral division turned into digits
+func normalizeRank(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > 1 {
+		if digits, ok := romanDivisions[words[len(words)-1]]; ok {
+			words[len(words)-1] = digits
+		}
+	}
+	return normalize(strings.Join(words, ""))
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"github.com/1shoukr/swiftplay-backend/internal/validation"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// updateMeRequest is the partial update payload for the caller's own account.
+// Omitted fields are left unchanged.
+type updateMeRequest struct {
+	User struct {
+		Username *string `json:"username"`
+	} `json:"user"`
+	Profile profileRequest `json:"profile"`
+}
+
+// GetMe returns the caller's stored user and profile
+func (h *UserHandler) GetMe(c *gin.Context) {
+	userID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	user, profile, err := h.loadAccount(h.db, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "User not found",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load user",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user":    user,
+		"profile": profile,
+	})
+}
+
+// UpdateMe applies a partial update to the caller's username and profile
+func (h *UserHandler) UpdateMe(c *gin.Context) {
+	userID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	var requestData updateMeRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	fieldErrors := validation.FieldErrors{}
+	if requestData.User.Username != nil {
+		username := strings.TrimSpace(*requestData.User.Username)
+		requestData.User.Username = &username
+		validateUsername(fieldErrors, "user.username", username)
+	}
+	requestData.Profile.validate(fieldErrors, "profile", h.catalog)
+
+	if fieldErrors.HasErrors() {
+		fieldErrors.Respond(c)
+		return
+	}
+
+	var user *models.User
+	var profile *models.Profile
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		user, profile, err = h.loadAccount(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		if username := requestData.User.Username; username != nil && *username != user.Username {
+			taken, err := usernameTaken(tx, *username, userID)
+			if err != nil {
+				return err
+			}
+			if taken {
+				return validation.FieldErrors{"user.username": "username is already taken"}
+			}
+
+			if err := tx.Model(user).Update("username", *username).Error; err != nil {
+				return err
+			}
+		}
+
+		if profile == nil {
+			profile = &models.Profile{UserID: userID, GameRanks: map[string]string{}, GameRankValues: map[string]int{}, Privacy: models.DefaultProfilePrivacy()}
+		}
+		requestData.Profile.applyTo(profile)
+		return tx.Save(profile).Error
+	})
+
+	var conflict validation.FieldErrors
+	switch {
+	case errors.As(err, &conflict):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":  "Username is already taken",
+			"fields": conflict,
+		})
+		return
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "User not found",
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to update account",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Account updated successfully",
+		"user":    user,
+		"profile": profile,
+	})
+}
+
+// DeleteMe soft-deletes the caller's account after confirming their password.
+// The account is hidden and signed out immediately, and purged permanently once
+// ACCOUNT_DELETION_GRACE_PERIOD has passed.
+func (h *UserHandler) DeleteMe(c *gin.Context) {
+	userID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	var requestData struct {
+		Password string `json:"password" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var user models.User
+	if err := h.db.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "User not found",
+		})
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(requestData.Password)); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Password is incorrect",
+		})
+		return
+	}
+
+	now := time.Now()
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Update("soft_delete", true).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&user).Error; err != nil {
+			return err
+		}
+		return revokeUserRefreshTokens(tx, userID, now)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to delete account",
+		})
+		return
+	}
+
+	h.userStates.Invalidate(userID)
+	if err := h.jwtService.RevokeAllForUser(c.Request.Context(), userID); err != nil {
+		log.Printf("Failed to revoke access tokens for user %d: %v", userID, err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Account deleted",
+		"purge_after": now.Add(h.authConfig.DeletionGracePeriod),
+	})
+}
+
+// loadAccount loads the user and, when one exists, their profile
+func (h *UserHandler) loadAccount(db *gorm.DB, userID uint) (*models.User, *models.Profile, error) {
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var profiles []models.Profile
+	if err := db.Where("user_id = ?", userID).Limit(1).Find(&profiles).Error; err != nil {
+		return nil, nil, err
+	}
+
+	if len(profiles) == 0 {
+		return &user, nil, nil
+	}
+	return &user, &profiles[0], nil
+}
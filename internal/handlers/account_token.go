@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var errAccountTokenInvalid = errors.New("token is invalid, expired or already used")
+
+// issueAccountToken creates a new single-use token for the user, invalidating any
+// unused token previously issued for the same purpose. It returns the plaintext
+// token, which is only ever sent to the user.
+func issueAccountToken(db *gorm.DB, userID uint, purpose string, ttl time.Duration) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	now := time.Now()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.AccountToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+			Update("used_at", now).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.AccountToken{
+			UserID:    userID,
+			Purpose:   purpose,
+			TokenHash: hashAccountToken(token),
+			ExpiresAt: now.Add(ttl),
+		}).Error
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// consumeAccountToken marks a valid token as used and returns it. It must be
+// called inside a transaction so the token is only consumed if the caller's
+// change commits.
+func consumeAccountToken(tx *gorm.DB, token, purpose string) (*models.AccountToken, error) {
+	var stored models.AccountToken
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ? AND purpose = ?", hashAccountToken(token), purpose).
+		First(&stored).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errAccountTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if stored.UsedAt != nil || now.After(stored.ExpiresAt) {
+		return nil, errAccountTokenInvalid
+	}
+
+	if err := tx.Model(&stored).Update("used_at", now).Error; err != nil {
+		return nil, err
+	}
+
+	return &stored, nil
+}
+
+func hashAccountToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,763 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/1shoukr/swiftplay-backend/internal/audit"
+	"github.com/1shoukr/swiftplay-backend/internal/database"
+	"github.com/1shoukr/swiftplay-backend/internal/jwt"
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"github.com/1shoukr/swiftplay-backend/internal/rbac"
+	"github.com/1shoukr/swiftplay-backend/internal/realtime"
+	"github.com/1shoukr/swiftplay-backend/internal/userstate"
+	"github.com/1shoukr/swiftplay-backend/internal/validation"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Account states shown in the admin console
+const (
+	accountActive    = "active"
+	accountSuspended = "suspended"
+	accountBanned    = "banned"
+	accountDeleted   = "deleted"
+)
+
+// maxSuspension is the longest suspension; longer ones should be bans
+const maxSuspension = 365 * 24 * time.Hour
+
+// adminAuditEvents maps each admin action to the event it is audited as
+var adminAuditEvents = map[string]string{
+	models.AdminActionSuspend:    audit.EventSuspend,
+	models.AdminActionBan:        audit.EventBan,
+	models.AdminActionReinstate:  audit.EventReinstate,
+	models.AdminActionRestore:    audit.EventRestore,
+	models.AdminActionRoleChange: audit.EventRoleChange,
+}
+
+type AdminHandler struct {
+	db         *gorm.DB
+	jwtService *jwt.JWTService
+	userStates *userstate.Cache
+	policy     *rbac.Policy
+	hub        *realtime.Hub
+	stream     *realtime.Stream
+	auditLog   *audit.Logger
+}
+
+func NewAdminHandler(db *database.Database, jwtService *jwt.JWTService, userStates *userstate.Cache, policy *rbac.Policy, hub *realtime.Hub, stream *realtime.Stream, auditLog *audit.Logger) *AdminHandler {
+	return &AdminHandler{
+		db:         db.GetDB(),
+		jwtService: jwtService,
+		userStates: userStates,
+		policy:     policy,
+		hub:        hub,
+		stream:     stream,
+		auditLog:   auditLog,
+	}
+}
+
+// adminUserView is a user account as shown in the admin console
+type adminUserView struct {
+	models.User
+	Status string `json:"status"`
+}
+
+// ListUsers searches accounts by username or email, role and account state.
+// Deleted accounts waiting to be purged are included.
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	var params struct {
+		Query  string `form:"q"`
+		Role   string `form:"role"`
+		Status string `form:"status"`
+		Limit  int    `form:"limit"`
+		Cursor string `form:"cursor"`
+	}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	fieldErrors := validation.FieldErrors{}
+	if params.Role != "" && !h.policy.HasRole(params.Role) {
+		fieldErrors.Add("role", "must be one of "+strings.Join(h.policy.Roles(), ", "))
+	}
+	beforeID, err := decodeCursor(params.Cursor)
+	if err != nil {
+		fieldErrors.Add("cursor", err.Error())
+	}
+
+	now := time.Now()
+	query := h.db.Unscoped().Model(&models.User{})
+	switch params.Status {
+	case "":
+	case accountActive:
+		query = query.Where("deleted_at IS NULL AND soft_delete = ? AND banned_at IS NULL AND (suspended_until IS NULL OR suspended_until <= ?)", false, now)
+	case accountSuspended:
+		query = query.Where("deleted_at IS NULL AND banned_at IS NULL AND suspended_until > ?", now)
+	case accountBanned:
+		query = query.Where("deleted_at IS NULL AND banned_at IS NOT NULL")
+	case accountDeleted:
+		query = query.Where("deleted_at IS NOT NULL")
+	default:
+		fieldErrors.Add("status", "must be one of active, suspended, banned, deleted")
+	}
+	if fieldErrors.HasErrors() {
+		fieldErrors.Respond(c)
+		return
+	}
+
+	if search := strings.TrimSpace(params.Query); search != "" {
+		pattern := "%" + escapeLike(strings.ToLower(search)) + "%"
+		query = query.Where("LOWER(username) LIKE ? OR LOWER(email) LIKE ?", pattern, pattern)
+	}
+	if params.Role != "" {
+		query = query.Where("auth_level = ?", params.Role)
+	}
+	if beforeID != 0 {
+		query = query.Where("user_id < ?", beforeID)
+	}
+
+	limit := pageLimit(params.Limit, 20, 100)
+	var users []models.User
+	if err := query.Order("user_id DESC").Limit(limit + 1).Find(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load users",
+		})
+		return
+	}
+
+	var nextCursor *string
+	if len(users) > limit {
+		users = users[:limit]
+		cursor := encodeCursor(users[len(users)-1].UserID)
+		nextCursor = &cursor
+	}
+
+	views := make([]adminUserView, 0, len(users))
+	for _, user := range users {
+		views = append(views, adminUserView{User: user, Status: accountStatus(&user, now)})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users":       views,
+		"next_cursor": nextCursor,
+	})
+}
+
+// GetUser returns an account with its profile, the number of open reports about
+// it and the latest admin actions taken on it
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	user, ok := h.loadUser(c)
+	if !ok {
+		return
+	}
+
+	var profiles []models.Profile
+	if err := h.db.Where("user_id = ?", user.UserID).Limit(1).Find(&profiles).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load user",
+		})
+		return
+	}
+
+	var openReports int64
+	if err := h.db.Model(&models.Report{}).
+		Where("reported_id = ? AND status = ?", user.UserID, models.ReportStatusOpen).
+		Count(&openReports).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load user",
+		})
+		return
+	}
+
+	actions := []models.AdminAction{}
+	if err := h.db.Where("target_id = ?", user.UserID).
+		Order("action_id DESC").Limit(20).Find(&actions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load user",
+		})
+		return
+	}
+
+	var profile *models.Profile
+	if len(profiles) > 0 {
+		profile = &profiles[0]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user":         adminUserView{User: *user, Status: accountStatus(user, time.Now())},
+		"profile":      profile,
+		"open_reports": openReports,
+		"actions":      actions,
+	})
+}
+
+// ListUserReports returns the reports filed about an account, newest first
+func (h *AdminHandler) ListUserReports(c *gin.Context) {
+	user, ok := h.loadUser(c)
+	if !ok {
+		return
+	}
+
+	limit, beforeID, ok := pageParams(c, 20, 100)
+	if !ok {
+		return
+	}
+
+	query := h.db.Where("reported_id = ?", user.UserID)
+	if beforeID != 0 {
+		query = query.Where("report_id < ?", beforeID)
+	}
+
+	var reports []models.Report
+	if err := query.Order("report_id DESC").Limit(limit + 1).Find(&reports).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load reports",
+		})
+		return
+	}
+
+	var nextCursor *string
+	if len(reports) > limit {
+		reports = reports[:limit]
+		cursor := encodeCursor(reports[len(reports)-1].ReportID)
+		nextCursor = &cursor
+	}
+
+	views, err := reportViews(h.db, reports)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load reports",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reports":     views,
+		"next_cursor": nextCursor,
+	})
+}
+
+// ListUserMessages returns the messages an account sent, newest first
+func (h *AdminHandler) ListUserMessages(c *gin.Context) {
+	user, ok := h.loadUser(c)
+	if !ok {
+		return
+	}
+
+	limit, beforeID, ok := pageParams(c, 50, 100)
+	if !ok {
+		return
+	}
+
+	query := h.db.Where("sender_id = ?", user.UserID)
+	if beforeID != 0 {
+		query = query.Where("message_id < ?", beforeID)
+	}
+
+	var messages []models.Message
+	if err := query.Order("message_id DESC").Limit(limit + 1).Find(&messages).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load messages",
+		})
+		return
+	}
+
+	var nextCursor *string
+	if len(messages) > limit {
+		messages = messages[:limit]
+		cursor := encodeCursor(messages[len(messages)-1].MessageID)
+		nextCursor = &cursor
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"messages":    messages,
+		"next_cursor": nextCursor,
+	})
+}
+
+// SuspendUser locks an account out until the given time. Suspending an account
+// that is already suspended replaces the end of the suspension.
+func (h *AdminHandler) SuspendUser(c *gin.Context) {
+	var requestData struct {
+		Until  time.Time `json:"until" binding:"required"`
+		Reason string    `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	now := time.Now()
+	fieldErrors := validation.FieldErrors{}
+	if !requestData.Until.After(now) {
+		fieldErrors.Add("until", "must be in the future")
+	} else if requestData.Until.Sub(now) > maxSuspension {
+		fieldErrors.Add("until", "suspensions can last at most 365 days; ban the account instead")
+	}
+	reason := validateReason(fieldErrors, requestData.Reason, true)
+	if fieldErrors.HasErrors() {
+		fieldErrors.Respond(c)
+		return
+	}
+
+	actorID, target, ok := h.managedUser(c)
+	if !ok {
+		return
+	}
+	if target.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Account is deleted; restore it first",
+		})
+		return
+	}
+	if target.IsBanned() {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Account is banned",
+		})
+		return
+	}
+
+	until := requestData.Until.UTC()
+	if !h.act(c, actorID, target, models.AdminActionSuspend, reason,
+		map[string]interface{}{"suspended_until": until},
+		map[string]interface{}{"until": until, "previous_until": target.SuspendedUntil},
+		true) {
+		return
+	}
+	target.SuspendedUntil = &until
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User suspended",
+		"user":    adminUserView{User: *target, Status: accountStatus(target, now)},
+	})
+}
+
+// BanUser locks an account out permanently
+func (h *AdminHandler) BanUser(c *gin.Context) {
+	var requestData struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	fieldErrors := validation.FieldErrors{}
+	reason := validateReason(fieldErrors, requestData.Reason, true)
+	if fieldErrors.HasErrors() {
+		fieldErrors.Respond(c)
+		return
+	}
+
+	actorID, target, ok := h.managedUser(c)
+	if !ok {
+		return
+	}
+	if target.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Account is deleted; restore it first",
+		})
+		return
+	}
+	if target.IsBanned() {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Account is already banned",
+		})
+		return
+	}
+
+	now := time.Now()
+	if !h.act(c, actorID, target, models.AdminActionBan, reason,
+		map[string]interface{}{"banned_at": now},
+		nil,
+		true) {
+		return
+	}
+	target.BannedAt = &now
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User banned",
+		"user":    adminUserView{User: *target, Status: accountStatus(target, now)},
+	})
+}
+
+// ReinstateUser lifts a suspension or ban. Lifting a ban needs users:ban.
+func (h *AdminHandler) ReinstateUser(c *gin.Context) {
+	var requestData struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	fieldErrors := validation.FieldErrors{}
+	reason := validateReason(fieldErrors, requestData.Reason, false)
+	if fieldErrors.HasErrors() {
+		fieldErrors.Respond(c)
+		return
+	}
+
+	actorID, target, ok := h.managedUser(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	if !target.IsBanned() && !target.IsSuspended(now) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Account is not suspended or banned",
+		})
+		return
+	}
+
+	_, _, actorRole, _ := middleware.GetUserFromContext(c)
+	if target.IsBanned() && !h.policy.HasPermission(actorRole, rbac.PermUsersBan) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":    "Insufficient permissions",
+			"required": []string{rbac.PermUsersBan},
+		})
+		return
+	}
+
+	if !h.act(c, actorID, target, models.AdminActionReinstate, reason,
+		map[string]interface{}{"suspended_until": nil, "banned_at": nil},
+		map[string]interface{}{"suspended_until": target.SuspendedUntil, "banned_at": target.BannedAt},
+		false) {
+		return
+	}
+	target.SuspendedUntil = nil
+	target.BannedAt = nil
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User reinstated",
+		"user":    adminUserView{User: *target, Status: accountStatus(target, now)},
+	})
+}
+
+// RestoreUser brings back an account its owner deleted, as long as it has not
+// been purged yet
+func (h *AdminHandler) RestoreUser(c *gin.Context) {
+	var requestData struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	fieldErrors := validation.FieldErrors{}
+	reason := validateReason(fieldErrors, requestData.Reason, false)
+	if fieldErrors.HasErrors() {
+		fieldErrors.Respond(c)
+		return
+	}
+
+	actorID, target, ok := h.managedUser(c)
+	if !ok {
+		return
+	}
+	if !target.DeletedAt.Valid && !target.SoftDelete {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Account is not deleted",
+		})
+		return
+	}
+
+	if !h.act(c, actorID, target, models.AdminActionRestore, reason,
+		map[string]interface{}{"soft_delete": false, "deleted_at": nil},
+		map[string]interface{}{"deleted_at": target.DeletedAt.Time},
+		false) {
+		return
+	}
+	target.SoftDelete = false
+	target.DeletedAt = gorm.DeletedAt{}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User restored",
+		"user":    adminUserView{User: *target, Status: accountStatus(target, time.Now())},
+	})
+}
+
+// ChangeRole sets an account's auth level. Admins can grant roles up to their
+// own and only manage accounts below their own role, except that holders of
+// roles:assign_engineer can also manage their own role. The user's sessions are
+// revoked so that new tokens carry the new role.
+func (h *AdminHandler) ChangeRole(c *gin.Context) {
+	var requestData struct {
+		AuthLevel string `json:"auth_level"`
+		Reason    string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	fieldErrors := validation.FieldErrors{}
+	if !h.policy.HasRole(requestData.AuthLevel) {
+		fieldErrors.Add("auth_level", "must be one of "+strings.Join(h.policy.Roles(), ", "))
+	}
+	reason := validateReason(fieldErrors, requestData.Reason, false)
+	if fieldErrors.HasErrors() {
+		fieldErrors.Respond(c)
+		return
+	}
+
+	actorID, target, ok := h.managedUser(c)
+	if !ok {
+		return
+	}
+	if target.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Account is deleted; restore it first",
+		})
+		return
+	}
+	if target.AuthLevel == requestData.AuthLevel {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "User already has that role",
+		})
+		return
+	}
+
+	_, _, actorRole, _ := middleware.GetUserFromContext(c)
+	if !h.canGrant(actorRole, requestData.AuthLevel) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "You cannot grant that role",
+		})
+		return
+	}
+
+	previous := target.AuthLevel
+	if !h.act(c, actorID, target, models.AdminActionRoleChange, reason,
+		map[string]interface{}{"auth_level": requestData.AuthLevel},
+		map[string]interface{}{"from": previous, "to": requestData.AuthLevel},
+		true) {
+		return
+	}
+	target.AuthLevel = requestData.AuthLevel
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Role updated",
+		"user":    adminUserView{User: *target, Status: accountStatus(target, time.Now())},
+	})
+}
+
+// canGrant reports whether the actor's role may hand out the role. Roles up to
+// the actor's own can be granted, so an admin can make admins but not super
+// admins. A role holding roles:assign_engineer, engineer in the default policy,
+// can only be granted by its holders.
+func (h *AdminHandler) canGrant(actorRole, role string) bool {
+	if h.policy.Outranks(role, actorRole) {
+		return false
+	}
+	if h.policy.HasPermission(role, rbac.PermRolesAssignEngineer) {
+		return h.policy.HasPermission(actorRole, rbac.PermRolesAssignEngineer)
+	}
+	return true
+}
+
+// act applies the updates to the target's account and records the action in
+// one transaction. When endSessions is set, the user's tokens are revoked and
+// their live connections closed afterwards. It writes the error response itself
+// and reports whether the request may continue.
+func (h *AdminHandler) act(c *gin.Context, actorID uint, target *models.User, action, reason string, updates, details map[string]interface{}, endSessions bool) bool {
+	now := time.Now()
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.User{}).
+			Where("user_id = ?", target.UserID).
+			Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.AdminAction{
+			ActorID:  actorID,
+			TargetID: target.UserID,
+			Action:   action,
+			Reason:   reason,
+			Details:  details,
+		}).Error; err != nil {
+			return err
+		}
+		if endSessions {
+			return revokeUserRefreshTokens(tx, target.UserID, now)
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to update user",
+		})
+		return false
+	}
+
+	auditDetails := map[string]interface{}{"reason": reason}
+	for key, value := range details {
+		auditDetails[key] = value
+	}
+	h.auditLog.RecordRequest(c, audit.Entry{
+		Event:    adminAuditEvents[action],
+		ActorID:  actorID,
+		TargetID: target.UserID,
+		Outcome:  models.AuditOutcomeSuccess,
+		Details:  auditDetails,
+	})
+
+	h.userStates.Invalidate(target.UserID)
+	if endSessions {
+		if err := h.jwtService.RevokeAllForUser(c.Request.Context(), target.UserID); err != nil {
+			log.Printf("Failed to revoke access tokens for user %d: %v", target.UserID, err)
+		}
+		h.hub.Disconnect(target.UserID)
+		h.stream.Disconnect(target.UserID)
+	}
+	return true
+}
+
+// managedUser loads the account named in the URL for a change by the caller, who
+// must outrank its current role and cannot act on themselves. It writes the
+// error response itself and reports whether the request may continue.
+func (h *AdminHandler) managedUser(c *gin.Context) (uint, *models.User, bool) {
+	actorID, _, actorRole, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return 0, nil, false
+	}
+
+	target, ok := h.loadUser(c)
+	if !ok {
+		return 0, nil, false
+	}
+
+	if target.UserID == actorID {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "You cannot change your own account",
+		})
+		return 0, nil, false
+	}
+	if !h.policy.Outranks(actorRole, target.AuthLevel) && !h.isPeerManager(actorRole, target.AuthLevel) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "You can only manage accounts below your own role",
+		})
+		return 0, nil, false
+	}
+	return actorID, target, true
+}
+
+// isPeerManager reports whether the actor may manage accounts of their own role,
+// so that the top of the hierarchy is not beyond everyone's reach
+func (h *AdminHandler) isPeerManager(actorRole, role string) bool {
+	return actorRole == role && h.policy.HasPermission(actorRole, rbac.PermRolesAssignEngineer)
+}
+
+// loadUser loads the account named in the URL, including deleted ones. It
+// writes the error response itself and reports whether the request may continue.
+func (h *AdminHandler) loadUser(c *gin.Context) (*models.User, bool) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user ID",
+		})
+		return nil, false
+	}
+
+	var users []models.User
+	if err := h.db.Unscoped().Where("user_id = ?", userID).Limit(1).Find(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load user",
+		})
+		return nil, false
+	}
+	if len(users) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "User not found",
+		})
+		return nil, false
+	}
+
+	audit.SetTarget(c, users[0].UserID)
+	return &users[0], true
+}
+
+// accountStatus summarizes whether the account can be used
+func accountStatus(user *models.User, now time.Time) string {
+	switch {
+	case user.DeletedAt.Valid || user.SoftDelete:
+		return accountDeleted
+	case user.IsBanned():
+		return accountBanned
+	case user.IsSuspended(now):
+		return accountSuspended
+	default:
+		return accountActive
+	}
+}
+
+// validateReason trims an action's reason and checks its length
+func validateReason(fieldErrors validation.FieldErrors, reason string, required bool) string {
+	reason = strings.TrimSpace(reason)
+	if required && reason == "" {
+		fieldErrors.Add("reason", "reason is required")
+	}
+	if utf8.RuneCountInString(reason) > maxReportDetails {
+		fieldErrors.Add("reason", "must be at most 1000 characters")
+	}
+	return reason
+}
+
+// pageParams binds the limit and cursor query parameters. It writes the error
+// response itself and reports whether the request may continue.
+func pageParams(c *gin.Context, fallback, maximum int) (int, uint, bool) {
+	var params struct {
+		Limit  int    `form:"limit"`
+		Cursor string `form:"cursor"`
+	}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return 0, 0, false
+	}
+
+	cursorID, err := decodeCursor(params.Cursor)
+	if err != nil {
+		fieldErrors := validation.FieldErrors{}
+		fieldErrors.Add("cursor", err.Error())
+		fieldErrors.Respond(c)
+		return 0, 0, false
+	}
+	return pageLimit(params.Limit, fallback, maximum), cursorID, true
+}
+
+// escapeLike escapes the LIKE wildcards in a search term
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(term)
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/database"
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"github.com/1shoukr/swiftplay-backend/internal/validation"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type AuditHandler struct {
+	db *gorm.DB
+}
+
+func NewAuditHandler(db *database.Database) *AuditHandler {
+	return &AuditHandler{
+		db: db.GetDB(),
+	}
+}
+
+// auditRecordView is an audit record with its details decoded
+type auditRecordView struct {
+	models.AuditRecord
+	Details json.RawMessage `json:"details,omitempty"`
+}
+
+// ListAuditRecords searches the audit log, newest first, by event, actor,
+// target, outcome, IP address and time range
+func (h *AuditHandler) ListAuditRecords(c *gin.Context) {
+	var params struct {
+		Event    string `form:"event"`
+		ActorID  *uint  `form:"actor_id"`
+		TargetID *uint  `form:"target_id"`
+		Outcome  string `form:"outcome"`
+		IP       string `form:"ip"`
+		Since    string `form:"since"`
+		Until    string `form:"until"`
+		Limit    int    `form:"limit"`
+		Cursor   string `form:"cursor"`
+	}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	fieldErrors := validation.FieldErrors{}
+	query := h.db.Model(&models.AuditRecord{})
+
+	if event := strings.TrimSpace(params.Event); event != "" {
+		// A trailing dot selects a whole family of events, such as "auth."
+		if strings.HasSuffix(event, ".") {
+			query = query.Where("event LIKE ?", escapeLike(event)+"%")
+		} else {
+			query = query.Where("event = ?", event)
+		}
+	}
+	if params.ActorID != nil {
+		query = query.Where("actor_id = ?", *params.ActorID)
+	}
+	if params.TargetID != nil {
+		query = query.Where("target_id = ?", *params.TargetID)
+	}
+	switch params.Outcome {
+	case "":
+	case models.AuditOutcomeSuccess, models.AuditOutcomeFailure, models.AuditOutcomeDenied:
+		query = query.Where("outcome = ?", params.Outcome)
+	default:
+		fieldErrors.Add("outcome", "must be one of success, failure, denied")
+	}
+	if ip := strings.TrimSpace(params.IP); ip != "" {
+		query = query.Where("ip = ?", ip)
+	}
+	if params.Since != "" {
+		if since, err := time.Parse(time.RFC3339, params.Since); err != nil {
+			fieldErrors.Add("since", "must be an RFC 3339 timestamp")
+		} else {
+			query = query.Where("created_at >= ?", since)
+		}
+	}
+	if params.Until != "" {
+		if until, err := time.Parse(time.RFC3339, params.Until); err != nil {
+			fieldErrors.Add("until", "must be an RFC 3339 timestamp")
+		} else {
+			query = query.Where("created_at < ?", until)
+		}
+	}
+
+	beforeID, err := decodeCursor(params.Cursor)
+	if err != nil {
+		fieldErrors.Add("cursor", err.Error())
+	}
+	if fieldErrors.HasErrors() {
+		fieldErrors.Respond(c)
+		return
+	}
+
+	if beforeID != 0 {
+		query = query.Where("record_id < ?", beforeID)
+	}
+
+	limit := pageLimit(params.Limit, 50, 200)
+	var records []models.AuditRecord
+	if err := query.Order("record_id DESC").Limit(limit + 1).Find(&records).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load audit records",
+		})
+		return
+	}
+
+	var nextCursor *string
+	if len(records) > limit {
+		records = records[:limit]
+		cursor := encodeCursor(records[len(records)-1].RecordID)
+		nextCursor = &cursor
+	}
+
+	views := make([]auditRecordView, 0, len(records))
+	for _, record := range records {
+		view := auditRecordView{AuditRecord: record}
+		if record.Details != "" {
+			view.Details = json.RawMessage(record.Details)
+		}
+		views = append(views, view)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"records":     views,
+		"next_cursor": nextCursor,
+	})
+}
@@ -1,24 +1,468 @@
 package handlers
 
 import (
+	"errors"
+	"io"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/1shoukr/swiftplay-backend/internal/audit"
+	"github.com/1shoukr/swiftplay-backend/internal/config"
 	"github.com/1shoukr/swiftplay-backend/internal/database"
+	"github.com/1shoukr/swiftplay-backend/internal/jwt"
+	"github.com/1shoukr/swiftplay-backend/internal/mailer"
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"github.com/1shoukr/swiftplay-backend/internal/ratelimit"
+	"github.com/1shoukr/swiftplay-backend/internal/rbac"
 	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var (
+	errRefreshTokenInvalid = errors.New("refresh token is invalid or revoked")
+	errRefreshTokenReused  = errors.New("refresh token reuse detected")
+)
+
+// dummyPasswordHash is compared against when no user matches the login, so that
+// unknown accounts take as long to reject as wrong passwords
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("swiftplay-dummy-password"), bcrypt.DefaultCost)
+
 type AuthHandler struct {
-	db *gorm.DB
+	db         *gorm.DB
+	jwtService *jwt.JWTService
+	mailer     mailer.Mailer
+	policy     *rbac.Policy
+	authConfig *config.AuthConfig
+	auditLog   *audit.Logger
+	lockout    *ratelimit.Lockout
 }
 
-func NewAuthHandler(db *database.Database) *AuthHandler {
-	return &AuthHandler{db: db.GetDB()}
+func NewAuthHandler(db *database.Database, jwtService *jwt.JWTService, mailer mailer.Mailer, policy *rbac.Policy, authConfig *config.AuthConfig, auditLog *audit.Logger, lockout *ratelimit.Lockout) *AuthHandler {
+	return &AuthHandler{
+		db:         db.GetDB(),
+		jwtService: jwtService,
+		mailer:     mailer,
+		policy:     policy,
+		authConfig: authConfig,
+		auditLog:   auditLog,
+		lockout:    lockout,
+	}
 }
 
 func (h *AuthHandler) Login(c *gin.Context) {
+	var requestData struct {
+		Login    string `json:"login" binding:"required"`
+		Password string `json:"password" binding:"required"`
+		Audience string `json:"audience"`
+	}
+
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	login := strings.TrimSpace(requestData.Login)
+
+	var user models.User
+	err := h.db.Where("username = ? OR LOWER(email) = LOWER(?)", login, login).First(&user).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to look up user",
+		})
+		return
+	}
+
+	lockoutKey := loginLockoutKey(c, user.UserID, login)
+	if h.respondLockedOut(c, lockoutKey, user.UserID, login) {
+		return
+	}
+
+	if err != nil || user.SoftDelete {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(requestData.Password))
+		h.countLoginFailure(c, lockoutKey)
+		h.recordLoginFailure(c, user.UserID, login, "unknown_account")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Invalid login or password",
+		})
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(requestData.Password)); err != nil {
+		h.countLoginFailure(c, lockoutKey)
+		h.recordLoginFailure(c, user.UserID, login, "wrong_password")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Invalid login or password",
+		})
+		return
+	}
+
+	if user.IsBanned() {
+		h.recordLoginFailure(c, user.UserID, login, "banned")
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "This account has been banned",
+		})
+		return
+	}
+	if user.IsSuspended(time.Now()) {
+		h.recordLoginFailure(c, user.UserID, login, "suspended")
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":           "This account is suspended",
+			"suspended_until": user.SuspendedUntil,
+		})
+		return
+	}
+
+	audience, err := h.jwtService.LoginAudience(requestData.Audience, h.isElevated(user.AuthLevel))
+	if err != nil {
+		h.recordLoginFailure(c, user.UserID, login, "audience_not_allowed")
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Requested audience is not available for this account",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	mfaRequired, mfaEnrolled, err := h.requiresMFA(&user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load MFA settings",
+		})
+		return
+	}
+
+	if mfaRequired {
+		h.respondMFAPending(c, &user, mfaEnrolled, audience)
+		return
+	}
+
+	tokens, err := h.issueLoginTokens(&user, audience)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to generate tokens",
+		})
+		return
+	}
+
+	h.clearLoginFailures(c, lockoutKey)
+	h.auditLog.RecordRequest(c, audit.Entry{
+		Event:   audit.EventLogin,
+		ActorID: user.UserID,
+		Outcome: models.AuditOutcomeSuccess,
+		Details: map[string]interface{}{"audience": audience},
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Login successful",
+		"tokens":  tokens,
+		"user":    user,
+	})
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token pair.
+// Refresh tokens are single-use; presenting one that was already rotated
+// revokes the whole token family, logging out every session derived from it.
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var requestData struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	claims, err := h.jwtService.ValidateRefreshToken(requestData.RefreshToken)
+	if err != nil || claims.ID == "" {
+		h.recordRefresh(c, 0, models.AuditOutcomeFailure, "invalid_token")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Invalid or expired refresh token",
+		})
+		return
+	}
+
+	var tokens *jwt.TokenPair
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		var stored models.RefreshToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_id = ? AND user_id = ?", claims.ID, claims.UserID).
+			First(&stored).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errRefreshTokenInvalid
+			}
+			return err
+		}
+
+		now := time.Now()
+
+		if stored.RotatedAt != nil {
+			return errRefreshTokenReused
+		}
+
+		if stored.RevokedAt != nil || now.After(stored.ExpiresAt) {
+			return errRefreshTokenInvalid
+		}
+
+		var user models.User
+		if err := tx.First(&user, stored.UserID).Error; err != nil || user.SoftDelete || user.IsBanned() || user.IsSuspended(now) {
+			return errRefreshTokenInvalid
+		}
+
+		issued, err := h.issueTokens(tx, &user, stored.FamilyID, claims.Audience[0])
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&stored).Updates(map[string]interface{}{
+			"rotated_at":     now,
+			"replaced_by_id": issued.RefreshTokenID,
+		}).Error; err != nil {
+			return err
+		}
+
+		tokens = issued
+		return nil
+	})
+
+	switch {
+	case err == nil:
+		h.recordRefresh(c, claims.UserID, models.AuditOutcomeSuccess, "")
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Tokens refreshed successfully",
+			"tokens":  tokens,
+		})
+	case errors.Is(err, errRefreshTokenReused):
+		h.recordRefresh(c, claims.UserID, models.AuditOutcomeDenied, "token_reused")
+		// The detecting transaction was rolled back, so revoke the family on its own
+		if err := revokeTokenFamilyByToken(h.db, claims.ID, time.Now()); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to revoke refresh tokens",
+			})
+			return
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Refresh token has already been used. All sessions for this login have been revoked.",
+		})
+	case errors.Is(err, errRefreshTokenInvalid):
+		h.recordRefresh(c, claims.UserID, models.AuditOutcomeFailure, "invalid_token")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Invalid or expired refresh token",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to refresh tokens",
+		})
+	}
+}
+
+// Logout revokes the access token used for the request and, when supplied,
+// the refresh token family it was issued with
+func (h *AuthHandler) Logout(c *gin.Context) {
+	claims, exists := middleware.GetClaimsFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	var requestData struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	if err := c.ShouldBindJSON(&requestData); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.jwtService.RevokeToken(c.Request.Context(), claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to revoke access token",
+		})
+		return
+	}
+
+	if requestData.RefreshToken != "" {
+		refreshClaims, err := h.jwtService.ValidateRefreshToken(requestData.RefreshToken)
+		if err == nil && refreshClaims.UserID == claims.UserID {
+			err := revokeTokenFamilyByToken(h.db, refreshClaims.ID, time.Now())
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to revoke refresh token",
+				})
+				return
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Logged out successfully",
+	})
+}
+
+// LogoutAll revokes every access and refresh token issued to the caller
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	if err := revokeUserRefreshTokens(h.db, userID, time.Now()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to revoke refresh tokens",
+		})
+		return
+	}
+
+	if err := h.jwtService.RevokeAllForUser(c.Request.Context(), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to revoke access tokens",
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Login endpoint",
-		"status":  "success",
+		"message": "Logged out of all sessions successfully",
 	})
-}
\ No newline at end of file
+}
+
+// issueTokens generates a token pair for the user and records the refresh token
+// as a member of the given token family
+func (h *AuthHandler) issueTokens(db *gorm.DB, user *models.User, familyID, audience string) (*jwt.TokenPair, error) {
+	tokens, err := h.jwtService.GenerateTokenPair(user.UserID, user.Email, user.AuthLevel, audience)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken := models.RefreshToken{
+		TokenID:   tokens.RefreshTokenID,
+		UserID:    user.UserID,
+		FamilyID:  familyID,
+		ExpiresAt: tokens.RefreshTokenExpiresAt,
+	}
+	if err := db.Create(&refreshToken).Error; err != nil {
+		return nil, err
+	}
+
+	// Signing in and refreshing count as activity for recommendations
+	if err := db.Model(&models.User{}).Where("user_id = ?", user.UserID).
+		UpdateColumn("last_active_at", time.Now()).Error; err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// loginLockoutKey names what failed sign-ins are counted against: the account
+// when the login matched one, so that its username and email share one count,
+// and otherwise the login as typed. Counts are kept per client IP as well, so
+// that failures from one address cannot lock the owner out everywhere else.
+func loginLockoutKey(c *gin.Context, userID uint, login string) string {
+	key := "login:" + strings.ToLower(login)
+	if userID != 0 {
+		key = ratelimit.UserKey(userID)
+	}
+	return key + "|" + ratelimit.ByIP(c)
+}
+
+// respondLockedOut rejects the sign-in with 429 while the key is locked out after
+// repeated failures, and reports whether it did. Sign-ins go ahead when the
+// lockout cannot be checked.
+func (h *AuthHandler) respondLockedOut(c *gin.Context, key string, userID uint, login string) bool {
+	retryAfter, err := h.lockout.Check(c.Request.Context(), key)
+	if err != nil {
+		log.Printf("Failed to check login lockout: %v", err)
+		return false
+	}
+	if retryAfter <= 0 {
+		return false
+	}
+
+	h.recordLoginFailure(c, userID, login, "locked_out")
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       "Too many failed sign-in attempts, please try again later",
+		"retry_after": seconds,
+	})
+	return true
+}
+
+// countLoginFailure counts a failed sign-in towards the key's lockout
+func (h *AuthHandler) countLoginFailure(c *gin.Context, key string) {
+	if _, err := h.lockout.Fail(c.Request.Context(), key); err != nil {
+		log.Printf("Failed to record login failure: %v", err)
+	}
+}
+
+// clearLoginFailures resets the key's lockout after a successful sign-in
+func (h *AuthHandler) clearLoginFailures(c *gin.Context, key string) {
+	if err := h.lockout.Clear(c.Request.Context(), key); err != nil {
+		log.Printf("Failed to clear login failures: %v", err)
+	}
+}
+
+// recordLoginFailure audits a rejected sign-in. userID is zero when the login
+// matched no account.
+func (h *AuthHandler) recordLoginFailure(c *gin.Context, userID uint, login, reason string) {
+	h.auditLog.RecordRequest(c, audit.Entry{
+		Event:   audit.EventLoginFailed,
+		ActorID: userID,
+		Outcome: models.AuditOutcomeFailure,
+		Details: map[string]interface{}{"login": login, "reason": reason},
+	})
+}
+
+// recordRefresh audits a refresh token exchange
+func (h *AuthHandler) recordRefresh(c *gin.Context, userID uint, outcome, reason string) {
+	entry := audit.Entry{
+		Event:   audit.EventTokenRefresh,
+		ActorID: userID,
+		Outcome: outcome,
+	}
+	if reason != "" {
+		entry.Details = map[string]interface{}{"reason": reason}
+	}
+	h.auditLog.RecordRequest(c, entry)
+}
+
+// revokeTokenFamily revokes every still-active refresh token in a family
+func revokeTokenFamily(db *gorm.DB, familyID string, at time.Time) error {
+	return db.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", at).Error
+}
+
+// revokeTokenFamilyByToken revokes the family the given refresh token belongs to
+func revokeTokenFamilyByToken(db *gorm.DB, tokenID string, at time.Time) error {
+	var stored models.RefreshToken
+	if err := db.Where("token_id = ?", tokenID).First(&stored).Error; err != nil {
+		return err
+	}
+	return revokeTokenFamily(db, stored.FamilyID, at)
+}
+
+// revokeUserRefreshTokens revokes every still-active refresh token belonging to a user
+func revokeUserRefreshTokens(db *gorm.DB, userID uint, at time.Time) error {
+	return db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
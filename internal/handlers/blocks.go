@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/database"
+	"github.com/1shoukr/swiftplay-backend/internal/matches"
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type BlockHandler struct {
+	db      *gorm.DB
+	matches *matches.Service
+}
+
+func NewBlockHandler(db *database.Database, matchService *matches.Service) *BlockHandler {
+	return &BlockHandler{
+		db:      db.GetDB(),
+		matches: matchService,
+	}
+}
+
+// blockedPlayer is an entry in the caller's block list
+type blockedPlayer struct {
+	Username  string    `json:"username"`
+	BlockedAt time.Time `json:"blocked_at"`
+}
+
+// Block hides the player and the caller from each other and closes any match
+// between them
+func (h *BlockHandler) Block(c *gin.Context) {
+	userID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	target, ok := h.player(c, userID)
+	if !ok {
+		return
+	}
+
+	match, err := h.matches.Block(c.Request.Context(), userID, target.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to block player",
+		})
+		return
+	}
+
+	response := gin.H{
+		"message": "Player blocked",
+	}
+	if match != nil {
+		response["match"] = match
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// Unblock lifts the caller's block on the player. Matches closed by the block
+// stay closed.
+func (h *BlockHandler) Unblock(c *gin.Context) {
+	userID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	target, ok := h.player(c, userID)
+	if !ok {
+		return
+	}
+
+	unblocked, err := h.matches.Unblock(c.Request.Context(), userID, target.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to unblock player",
+		})
+		return
+	}
+	if !unblocked {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Player is not blocked",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Player unblocked",
+	})
+}
+
+// ListBlocks returns the players the caller blocked, most recent first
+func (h *BlockHandler) ListBlocks(c *gin.Context) {
+	userID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	blocked := []blockedPlayer{}
+	if err := h.db.Table("blocks").
+		Select("users.username, blocks.created_at AS blocked_at").
+		Joins("JOIN users ON users.user_id = blocks.blocked_id").
+		Where("blocks.blocker_id = ? AND users.deleted_at IS NULL", userID).
+		Order("blocks.created_at DESC").
+		Find(&blocked).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load blocked players",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"blocked": blocked,
+	})
+}
+
+// player loads the player named in the URL, rejecting the caller themselves. It
+// writes the error response itself and reports whether the request may continue.
+func (h *BlockHandler) player(c *gin.Context, userID uint) (*models.User, bool) {
+	target, err := findPlayer(h.db, strings.TrimSpace(c.Param("username")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load player",
+		})
+		return nil, false
+	}
+	if target == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Player not found",
+		})
+		return nil, false
+	}
+	if target.UserID == userID {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "You cannot block yourself",
+		})
+		return nil, false
+	}
+	return target, true
+}
+
+// findPlayer loads an active player by username, whether or not they are blocked
+func findPlayer(db *gorm.DB, username string) (*models.User, error) {
+	var users []models.User
+	if err := db.Where("LOWER(username) = LOWER(?) AND soft_delete = ?", username, false).
+		Limit(1).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, nil
+	}
+	return &users[0], nil
+}
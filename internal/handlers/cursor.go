@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+)
+
+var errInvalidCursor = errors.New("cursor is not valid")
+
+// encodeCursor turns the id of the last row on a page into an opaque cursor
+func encodeCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+// decodeCursor reverses encodeCursor. An empty cursor means the first page.
+func decodeCursor(cursor string) (uint, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, errInvalidCursor
+	}
+	id, err := strconv.ParseUint(string(raw), 10, 64)
+	if err != nil || id == 0 {
+		return 0, errInvalidCursor
+	}
+	return uint(id), nil
+}
+
+// pageLimit applies the default and maximum page size to a requested limit
+func pageLimit(requested, fallback, maximum int) int {
+	switch {
+	case requested <= 0:
+		return fallback
+	case requested > maximum:
+		return maximum
+	default:
+		return requested
+	}
+}
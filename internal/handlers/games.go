@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/1shoukr/swiftplay-backend/internal/games"
+	"github.com/gin-gonic/gin"
+)
+
+type GameHandler struct {
+	catalog *games.Catalog
+}
+
+func NewGameHandler(catalog *games.Catalog) *GameHandler {
+	return &GameHandler{catalog: catalog}
+}
+
+// ListGames returns every supported game with its rank ladder and regions
+func (h *GameHandler) ListGames(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"games": h.catalog.Games(),
+	})
+}
+
+// GetGame returns a single game by id, name or alias
+func (h *GameHandler) GetGame(c *gin.Context) {
+	game, ok := h.catalog.Game(c.Param("game"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Game not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"game": game,
+	})
+}
@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/database"
+	"github.com/1shoukr/swiftplay-backend/internal/matches"
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"github.com/1shoukr/swiftplay-backend/internal/validation"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type MatchHandler struct {
+	db      *gorm.DB
+	matches *matches.Service
+}
+
+func NewMatchHandler(db *database.Database, matchService *matches.Service) *MatchHandler {
+	return &MatchHandler{
+		db:      db.GetDB(),
+		matches: matchService,
+	}
+}
+
+// matchView is a match as shown to one of its players
+type matchView struct {
+	models.Match
+	Player publicProfile `json:"player"`
+}
+
+// Like records a like for the player and creates a pending match, or accepts
+// the match if they already liked the caller
+func (h *MatchHandler) Like(c *gin.Context) {
+	h.swipe(c, models.SwipeLike)
+}
+
+// SuperLike is a like that is highlighted to the other player
+func (h *MatchHandler) SuperLike(c *gin.Context) {
+	h.swipe(c, models.SwipeSuperLike)
+}
+
+// Pass hides the player from the caller's discovery results for PASS_COOLDOWN
+func (h *MatchHandler) Pass(c *gin.Context) {
+	h.swipe(c, models.SwipePass)
+}
+
+func (h *MatchHandler) swipe(c *gin.Context, action string) {
+	userID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	target, err := findVisiblePlayer(h.db, userID, strings.TrimSpace(c.Param("username")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load player",
+		})
+		return
+	}
+	if target == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Player not found",
+		})
+		return
+	}
+	if target.UserID == userID {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "You cannot swipe on yourself",
+		})
+		return
+	}
+
+	match, err := h.matches.Swipe(c.Request.Context(), userID, target.UserID, action)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to record swipe",
+		})
+		return
+	}
+
+	response := gin.H{
+		"action":  action,
+		"matched": match != nil && match.Status == models.MatchStatusAccepted,
+	}
+	if match != nil {
+		response["match"] = match
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ListMatches returns the caller's matches, newest first, optionally filtered by status
+func (h *MatchHandler) ListMatches(c *gin.Context) {
+	userID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	var params struct {
+		Status string `form:"status"`
+		Limit  int    `form:"limit"`
+		Cursor string `form:"cursor"`
+	}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	fieldErrors := validation.FieldErrors{}
+	if params.Status != "" && !matches.IsStatus(params.Status) {
+		fieldErrors.Add("status", "must be one of "+strings.Join(matches.Statuses(), ", "))
+	}
+	afterID, err := decodeCursor(params.Cursor)
+	if err != nil {
+		fieldErrors.Add("cursor", err.Error())
+	}
+	if fieldErrors.HasErrors() {
+		fieldErrors.Respond(c)
+		return
+	}
+
+	query := h.db.Where("user_id_1 = ? OR user_id_2 = ?", userID, userID)
+	if params.Status != "" {
+		query = query.Where("status = ?", params.Status)
+	}
+	if afterID != 0 {
+		query = query.Where("match_id < ?", afterID)
+	}
+
+	limit := pageLimit(params.Limit, 20, 50)
+	var page []models.Match
+	if err := query.Order("match_id DESC").Limit(limit + 1).Find(&page).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load matches",
+		})
+		return
+	}
+
+	var nextCursor *string
+	if len(page) > limit {
+		page = page[:limit]
+		cursor := encodeCursor(page[len(page)-1].MatchID)
+		nextCursor = &cursor
+	}
+
+	views, err := h.matchViews(userID, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load matches",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"matches":     views,
+		"next_cursor": nextCursor,
+	})
+}
+
+// Unmatch ends one of the caller's pending or accepted matches
+func (h *MatchHandler) Unmatch(c *gin.Context) {
+	userID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	matchID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid match ID",
+		})
+		return
+	}
+
+	match, err := h.matches.Unmatch(c.Request.Context(), uint(matchID), userID)
+	switch {
+	case errors.Is(err, matches.ErrMatchNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Match not found",
+		})
+		return
+	case errors.Is(err, matches.ErrInvalidTransition):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Match is already closed",
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to unmatch",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Unmatched successfully",
+		"match":   match,
+	})
+}
+
+// matchViews attaches the other player's public profile to each match
+func (h *MatchHandler) matchViews(userID uint, page []models.Match) ([]matchView, error) {
+	otherIDs := make([]uint, 0, len(page))
+	for i := range page {
+		otherIDs = append(otherIDs, page[i].OtherUser(userID))
+	}
+
+	users := make(map[uint]models.User, len(otherIDs))
+	profiles := make(map[uint]*models.Profile, len(otherIDs))
+	if len(otherIDs) > 0 {
+		var userRows []models.User
+		if err := h.db.Where("user_id IN ?", otherIDs).Find(&userRows).Error; err != nil {
+			return nil, err
+		}
+		for _, user := range userRows {
+			users[user.UserID] = user
+		}
+
+		var profileRows []models.Profile
+		if err := h.db.Where("user_id IN ?", otherIDs).Find(&profileRows).Error; err != nil {
+			return nil, err
+		}
+		for i := range profileRows {
+			profiles[profileRows[i].UserID] = &profileRows[i]
+		}
+	}
+
+	now := time.Now()
+	views := make([]matchView, 0, len(page))
+	for _, match := range page {
+		otherID := match.OtherUser(userID)
+		owner := users[otherID]
+		matched := match.Status == models.MatchStatusAccepted
+		views = append(views, matchView{
+			Match:  match,
+			Player: projectProfile(&owner, profiles[otherID], false, matched, now),
+		})
+	}
+	return views, nil
+}
+
+// findVisiblePlayer loads an active player by username, treating suspended and
+// banned players and players blocked in either direction as missing
+func findVisiblePlayer(db *gorm.DB, userID uint, username string) (*models.User, error) {
+	var users []models.User
+	if err := db.Where("LOWER(username) = LOWER(?) AND soft_delete = ?", username, false).
+		Where("banned_at IS NULL AND (suspended_until IS NULL OR suspended_until <= ?)", time.Now()).
+		Where(`NOT EXISTS (SELECT 1 FROM blocks WHERE
+			(blocks.blocker_id = ? AND blocks.blocked_id = users.user_id) OR
+			(blocks.blocker_id = users.user_id AND blocks.blocked_id = ?))`, userID, userID).
+		Limit(1).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, nil
+	}
+	return &users[0], nil
+}
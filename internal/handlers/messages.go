@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/1shoukr/swiftplay-backend/internal/chat"
+	"github.com/1shoukr/swiftplay-backend/internal/database"
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"github.com/1shoukr/swiftplay-backend/internal/validation"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type MessageHandler struct {
+	db   *gorm.DB
+	chat *chat.Service
+}
+
+func NewMessageHandler(db *database.Database, chatService *chat.Service) *MessageHandler {
+	return &MessageHandler{
+		db:   db.GetDB(),
+		chat: chatService,
+	}
+}
+
+// SendMessage posts a message to one of the caller's accepted matches
+func (h *MessageHandler) SendMessage(c *gin.Context) {
+	userID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	var requestData struct {
+		Content string `json:"content"`
+	}
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	matchID, ok := matchIDParam(c)
+	if !ok {
+		return
+	}
+
+	message, err := h.chat.Send(c.Request.Context(), matchID, userID, requestData.Content)
+	if err != nil {
+		if errors.Is(err, chat.ErrInvalidContent) {
+			fieldErrors := validation.FieldErrors{}
+			fieldErrors.Add("content", err.Error())
+			fieldErrors.Respond(c)
+			return
+		}
+		respondChatError(c, err, "Failed to send message")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": message,
+	})
+}
+
+// ListMessages returns a match's messages, newest first. Pass next_cursor as
+// cursor to page back through older messages.
+func (h *MessageHandler) ListMessages(c *gin.Context) {
+	userID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	var params struct {
+		Limit  int    `form:"limit"`
+		Cursor string `form:"cursor"`
+	}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	beforeID, err := decodeCursor(params.Cursor)
+	if err != nil {
+		fieldErrors := validation.FieldErrors{}
+		fieldErrors.Add("cursor", err.Error())
+		fieldErrors.Respond(c)
+		return
+	}
+
+	matchID, ok := matchIDParam(c)
+	if !ok {
+		return
+	}
+
+	match, err := h.chat.ActiveMatch(c.Request.Context(), matchID, userID)
+	if err != nil {
+		respondChatError(c, err, "Failed to load match")
+		return
+	}
+
+	query := h.db.Where("match_id = ?", match.MatchID)
+	if beforeID != 0 {
+		query = query.Where("message_id < ?", beforeID)
+	}
+
+	limit := pageLimit(params.Limit, 50, 100)
+	var messages []models.Message
+	if err := query.Order("message_id DESC").Limit(limit + 1).Find(&messages).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load messages",
+		})
+		return
+	}
+
+	var nextCursor *string
+	if len(messages) > limit {
+		messages = messages[:limit]
+		cursor := encodeCursor(messages[len(messages)-1].MessageID)
+		nextCursor = &cursor
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"messages":    messages,
+		"next_cursor": nextCursor,
+	})
+}
+
+// MarkMessagesRead sets read_at on every unread message the caller received in
+// the match, up to and including up_to_message_id
+func (h *MessageHandler) MarkMessagesRead(c *gin.Context) {
+	userID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	var requestData struct {
+		UpToMessageID uint `json:"up_to_message_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	matchID, ok := matchIDParam(c)
+	if !ok {
+		return
+	}
+
+	markedRead, readAt, err := h.chat.MarkRead(c.Request.Context(), matchID, userID, requestData.UpToMessageID)
+	if err != nil {
+		respondChatError(c, err, "Failed to mark messages as read")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"marked_read": markedRead,
+		"read_at":     readAt,
+	})
+}
+
+// matchIDParam parses the match ID in the URL. It writes the error response
+// itself and reports whether the request may continue.
+func matchIDParam(c *gin.Context) (uint, bool) {
+	matchID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid match ID",
+		})
+		return 0, false
+	}
+	return uint(matchID), true
+}
+
+// respondChatError maps the chat service's match errors to responses, using
+// fallback as the message for unexpected failures
+func respondChatError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, chat.ErrMatchNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Match not found",
+		})
+	case errors.Is(err, chat.ErrMatchNotActive):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Messages are only available in accepted matches",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fallback,
+		})
+	}
+}
@@ -0,0 +1,522 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/audit"
+	"github.com/1shoukr/swiftplay-backend/internal/jwt"
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"github.com/1shoukr/swiftplay-backend/internal/rbac"
+	"github.com/1shoukr/swiftplay-backend/internal/totp"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const recoveryCodeCount = 10
+
+// recoveryCodeAlphabet omits characters that are easily confused when read aloud or typed
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+var (
+	errMFACodeInvalid    = errors.New("invalid authentication code")
+	errMFAAlreadyEnabled = errors.New("multi-factor authentication is already enabled")
+)
+
+// isElevated reports whether the role can reach privileged routes, which is what
+// MFA_REQUIRED_FOR_ELEVATED_ROLES and the admin token audience key off
+func (h *AuthHandler) isElevated(role string) bool {
+	return h.policy.HasPermission(role, rbac.PermAdminAccess)
+}
+
+// requiresMFA reports whether the user must pass a second factor to log in, and
+// whether they have an enabled enrollment to do so with
+func (h *AuthHandler) requiresMFA(user *models.User) (required bool, enrolled bool, err error) {
+	enrollment, err := findMFA(h.db, user.UserID)
+	if err != nil {
+		return false, false, err
+	}
+
+	enrolled = enrollment != nil && enrollment.EnabledAt != nil
+	required = enrolled || (h.authConfig.MFARequiredForElevated && h.isElevated(user.AuthLevel))
+	return required, enrolled, nil
+}
+
+// respondMFAPending answers the password step of a login that needs a second factor
+func (h *AuthHandler) respondMFAPending(c *gin.Context, user *models.User, enrolled bool, audience string) {
+	token, expiresAt, err := h.jwtService.GenerateMFAPendingToken(user.UserID, user.Email, user.AuthLevel, audience)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to generate tokens",
+		})
+		return
+	}
+
+	message := "Multi-factor authentication required"
+	if !enrolled {
+		message = "Multi-factor authentication enrollment required for this account"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":                 message,
+		"mfa_required":            true,
+		"mfa_enrollment_required": !enrolled,
+		"mfa_token":               token,
+		"mfa_token_expires_at":    expiresAt,
+	})
+}
+
+// VerifyMFA completes a two-step login by exchanging an mfa_pending token and a
+// TOTP or recovery code for full tokens
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var requestData struct {
+		MFAToken     string `json:"mfa_token" binding:"required"`
+		Code         string `json:"code"`
+		RecoveryCode string `json:"recovery_code"`
+	}
+
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if requestData.Code == "" && requestData.RecoveryCode == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Either code or recovery_code is required",
+		})
+		return
+	}
+
+	claims, err := h.jwtService.ValidateMFAPendingToken(requestData.MFAToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Invalid or expired MFA token",
+		})
+		return
+	}
+
+	if revoked, err := h.jwtService.IsRevoked(c.Request.Context(), claims); err != nil || revoked {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Invalid or expired MFA token",
+		})
+		return
+	}
+
+	var user models.User
+	if err := h.db.First(&user, claims.UserID).Error; err != nil || user.SoftDelete || user.IsBanned() || user.IsSuspended(time.Now()) {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Invalid or expired MFA token",
+		})
+		return
+	}
+
+	// Wrong codes count towards the same lockout as wrong passwords
+	lockoutKey := loginLockoutKey(c, user.UserID, user.Username)
+	if h.respondLockedOut(c, lockoutKey, user.UserID, user.Username) {
+		return
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if requestData.RecoveryCode != "" {
+			return consumeRecoveryCode(tx, user.UserID, requestData.RecoveryCode)
+		}
+		return checkTOTP(tx, user.UserID, requestData.Code, true)
+	})
+	if errors.Is(err, errMFACodeInvalid) {
+		h.countLoginFailure(c, lockoutKey)
+		h.recordLoginFailure(c, user.UserID, user.Username, "invalid_mfa_code")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Invalid authentication code",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to verify authentication code",
+		})
+		return
+	}
+
+	// The pending token is single-use
+	if err := h.jwtService.RevokeToken(c.Request.Context(), claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to revoke MFA token",
+		})
+		return
+	}
+
+	tokens, err := h.issueLoginTokens(&user, claims.Audience[0])
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to generate tokens",
+		})
+		return
+	}
+
+	h.clearLoginFailures(c, lockoutKey)
+	h.auditLog.RecordRequest(c, audit.Entry{
+		Event:   audit.EventLogin,
+		ActorID: user.UserID,
+		Outcome: models.AuditOutcomeSuccess,
+		Details: map[string]interface{}{"audience": claims.Audience[0], "mfa": true},
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Login successful",
+		"tokens":  tokens,
+		"user":    user,
+	})
+}
+
+// EnrollMFA starts TOTP enrollment by generating a new secret for the caller
+func (h *AuthHandler) EnrollMFA(c *gin.Context) {
+	userID, email, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	enrollment, err := findMFA(h.db, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load MFA settings",
+		})
+		return
+	}
+
+	if enrollment != nil && enrollment.EnabledAt != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Multi-factor authentication is already enabled",
+		})
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to generate MFA secret",
+		})
+		return
+	}
+
+	if err := h.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "enabled_at", "last_used_step", "updated_at"}),
+	}).Create(&models.UserMFA{UserID: userID, Secret: secret}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to save MFA settings",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Scan the provisioning URI with an authenticator app, then confirm with a code",
+		"secret":           secret,
+		"provisioning_uri": totp.ProvisioningURI(h.authConfig.MFAIssuer, email, secret),
+	})
+}
+
+// ConfirmMFA enables MFA once the caller proves their authenticator produces valid
+// codes, and returns a fresh set of recovery codes. Callers finishing a login with
+// an mfa_pending token also receive full tokens.
+func (h *AuthHandler) ConfirmMFA(c *gin.Context) {
+	claims, exists := middleware.GetClaimsFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	var requestData struct {
+		Code string `json:"code" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var recoveryCodes []string
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		enrollment, err := findMFA(tx, claims.UserID)
+		if err != nil {
+			return err
+		}
+		if enrollment != nil && enrollment.EnabledAt != nil {
+			return errMFAAlreadyEnabled
+		}
+
+		if err := checkTOTP(tx, claims.UserID, requestData.Code, false); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.UserMFA{}).Where("user_id = ?", claims.UserID).
+			Update("enabled_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		codes, err := replaceRecoveryCodes(tx, claims.UserID)
+		recoveryCodes = codes
+		return err
+	})
+	if errors.Is(err, errMFAAlreadyEnabled) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Multi-factor authentication is already enabled",
+		})
+		return
+	}
+	if errors.Is(err, errMFACodeInvalid) {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Invalid authentication code",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to enable multi-factor authentication",
+		})
+		return
+	}
+
+	response := gin.H{
+		"message":        "Multi-factor authentication enabled. Store these recovery codes somewhere safe; they will not be shown again.",
+		"recovery_codes": recoveryCodes,
+	}
+
+	if claims.TokenType == "mfa_pending" {
+		// The account may have been deleted, banned or suspended since the
+		// password step, as in VerifyMFA
+		var user models.User
+		if err := h.db.First(&user, claims.UserID).Error; err != nil || user.SoftDelete || user.IsBanned() || user.IsSuspended(time.Now()) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid or expired MFA token",
+			})
+			return
+		}
+
+		if err := h.jwtService.RevokeToken(c.Request.Context(), claims); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to revoke MFA token",
+			})
+			return
+		}
+
+		tokens, err := h.issueLoginTokens(&user, claims.Audience[0])
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to generate tokens",
+			})
+			return
+		}
+
+		// Confirming completes the sign-in the pending token was issued for
+		h.clearLoginFailures(c, loginLockoutKey(c, user.UserID, user.Username))
+		h.auditLog.RecordRequest(c, audit.Entry{
+			Event:   audit.EventLogin,
+			ActorID: user.UserID,
+			Outcome: models.AuditOutcomeSuccess,
+			Details: map[string]interface{}{"audience": claims.Audience[0], "mfa": true, "mfa_enrolled": true},
+		})
+
+		response["tokens"] = tokens
+		response["user"] = user
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DisableMFA removes the caller's enrollment after re-checking their password
+// and a current code. Accounts required to use MFA cannot disable it.
+func (h *AuthHandler) DisableMFA(c *gin.Context) {
+	userID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	var requestData struct {
+		Password string `json:"password" binding:"required"`
+		Code     string `json:"code" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var user models.User
+	if err := h.db.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "User not found",
+		})
+		return
+	}
+
+	if h.authConfig.MFARequiredForElevated && h.isElevated(user.AuthLevel) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Multi-factor authentication is required for your role and cannot be disabled",
+		})
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(requestData.Password)); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Password is incorrect",
+		})
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkTOTP(tx, userID, requestData.Code, true); err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.UserMFA{}).Error
+	})
+	if errors.Is(err, errMFACodeInvalid) {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Invalid authentication code",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to disable multi-factor authentication",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Multi-factor authentication disabled",
+	})
+}
+
+// issueLoginTokens starts a new refresh token family for a completed login
+func (h *AuthHandler) issueLoginTokens(user *models.User, audience string) (*jwt.TokenPair, error) {
+	familyID, err := jwt.NewTokenID()
+	if err != nil {
+		return nil, err
+	}
+	return h.issueTokens(h.db, user, familyID, audience)
+}
+
+// findMFA returns the user's enrollment, or nil when they have never enrolled
+func findMFA(db *gorm.DB, userID uint) (*models.UserMFA, error) {
+	var enrollments []models.UserMFA
+	if err := db.Where("user_id = ?", userID).Limit(1).Find(&enrollments).Error; err != nil {
+		return nil, err
+	}
+	if len(enrollments) == 0 {
+		return nil, nil
+	}
+	return &enrollments[0], nil
+}
+
+// checkTOTP validates a code against the user's secret and records its time step
+// so it cannot be replayed. When requireEnabled is false the check also accepts a
+// pending enrollment, which is how enrollment is confirmed.
+func checkTOTP(tx *gorm.DB, userID uint, code string, requireEnabled bool) error {
+	var enrollment models.UserMFA
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&enrollment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errMFACodeInvalid
+	}
+	if err != nil {
+		return err
+	}
+
+	if requireEnabled && enrollment.EnabledAt == nil {
+		return errMFACodeInvalid
+	}
+
+	step, ok := totp.Validate(enrollment.Secret, code, time.Now(), enrollment.LastUsedStep)
+	if !ok {
+		return errMFACodeInvalid
+	}
+
+	return tx.Model(&enrollment).Update("last_used_step", step).Error
+}
+
+// consumeRecoveryCode marks a matching unused recovery code as used
+func consumeRecoveryCode(tx *gorm.DB, userID uint, code string) error {
+	result := tx.Model(&models.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hashRecoveryCode(code)).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errMFACodeInvalid
+	}
+	return nil
+}
+
+// replaceRecoveryCodes discards the user's recovery codes and generates a new set,
+// returning the plaintext codes
+func replaceRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]models.MFARecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		records = append(records, models.MFARecoveryCode{UserID: userID, CodeHash: hashRecoveryCode(code)})
+	}
+
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// newRecoveryCode returns a random code formatted as xxxxx-xxxxx
+func newRecoveryCode() (string, error) {
+	raw := make([]byte, 10)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for i, v := range raw {
+		if i == 5 {
+			b.WriteByte('-')
+		}
+		b.WriteByte(recoveryCodeAlphabet[int(v)%len(recoveryCodeAlphabet)])
+	}
+	return b.String(), nil
+}
+
+// hashRecoveryCode hashes a recovery code, ignoring case, spaces and the separator
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.TrimSpace(code))
+	normalized = strings.NewReplacer(" ", "", "-", "").Replace(normalized)
+	return hashAccountToken(normalized)
+}
@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/audit"
+	"github.com/1shoukr/swiftplay-backend/internal/mailer"
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"github.com/1shoukr/swiftplay-backend/internal/password"
+	"github.com/1shoukr/swiftplay-backend/internal/validation"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// ForgotPassword emails a password reset link. The response is the same whether
+// or not the address belongs to an account, so it cannot be used to probe for users.
+// The link is issued and sent in the background so that the response time does
+// not give the account away either.
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var requestData struct {
+		Email string `json:"email" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var users []models.User
+	if err := h.db.Where("LOWER(email) = LOWER(?)", strings.TrimSpace(requestData.Email)).
+		Limit(1).Find(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to look up user",
+		})
+		return
+	}
+
+	if len(users) > 0 && !users[0].SoftDelete {
+		go func(user models.User) {
+			if err := h.sendPasswordResetEmail(context.Background(), &user); err != nil {
+				log.Printf("Failed to send password reset email to user %d: %v", user.UserID, err)
+			}
+		}(users[0])
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "If an account exists for that email, a password reset link has been sent",
+	})
+}
+
+// ResetPassword consumes a reset token and sets a new password, signing the user
+// out of every existing session
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var requestData struct {
+		Token       string `json:"token" binding:"required"`
+		NewPassword string `json:"new_password" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var user models.User
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		token, err := consumeAccountToken(tx, requestData.Token, models.TokenPurposePasswordReset)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.First(&user, token.UserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errAccountTokenInvalid
+			}
+			return err
+		}
+
+		if err := password.Validate(requestData.NewPassword, user.Username, user.Email); err != nil {
+			return validation.FieldErrors{"new_password": err.Error()}
+		}
+
+		updates := map[string]interface{}{}
+		// Receiving the reset link proves ownership of the address
+		if user.EmailVerifiedAt == nil {
+			updates["email_verified_at"] = time.Now()
+		}
+		return h.setPassword(tx, &user, requestData.NewPassword, updates)
+	})
+
+	var fieldErrors validation.FieldErrors
+	switch {
+	case errors.As(err, &fieldErrors):
+		fieldErrors.Respond(c)
+		return
+	case errors.Is(err, errAccountTokenInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Reset token is invalid or has expired",
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to reset password",
+		})
+		return
+	}
+
+	if err := h.jwtService.RevokeAllForUser(c.Request.Context(), user.UserID); err != nil {
+		log.Printf("Failed to revoke access tokens for user %d: %v", user.UserID, err)
+	}
+
+	h.auditLog.RecordRequest(c, audit.Entry{
+		Event:   audit.EventPasswordReset,
+		ActorID: user.UserID,
+		Outcome: models.AuditOutcomeSuccess,
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password reset successfully. Please log in with your new password.",
+	})
+}
+
+// ChangePassword sets a new password for the authenticated user after checking
+// the current one, signing them out of every existing session
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	var requestData struct {
+		CurrentPassword string `json:"current_password" binding:"required"`
+		NewPassword     string `json:"new_password" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var user models.User
+	if err := h.db.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "User not found",
+		})
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(requestData.CurrentPassword)); err != nil {
+		h.auditLog.RecordRequest(c, audit.Entry{
+			Event:   audit.EventPasswordChange,
+			ActorID: user.UserID,
+			Outcome: models.AuditOutcomeFailure,
+			Details: map[string]interface{}{"reason": "wrong_password"},
+		})
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Current password is incorrect",
+		})
+		return
+	}
+
+	fieldErrors := validation.FieldErrors{}
+	if requestData.NewPassword == requestData.CurrentPassword {
+		fieldErrors.Add("new_password", "new password must be different from the current password")
+	}
+	if err := password.Validate(requestData.NewPassword, user.Username, user.Email); err != nil {
+		fieldErrors.Add("new_password", err.Error())
+	}
+	if fieldErrors.HasErrors() {
+		fieldErrors.Respond(c)
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		return h.setPassword(tx, &user, requestData.NewPassword, map[string]interface{}{})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to change password",
+		})
+		return
+	}
+
+	if err := h.jwtService.RevokeAllForUser(c.Request.Context(), user.UserID); err != nil {
+		log.Printf("Failed to revoke access tokens for user %d: %v", user.UserID, err)
+	}
+
+	h.auditLog.RecordRequest(c, audit.Entry{
+		Event:   audit.EventPasswordChange,
+		ActorID: user.UserID,
+		Outcome: models.AuditOutcomeSuccess,
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password changed successfully. Please log in with your new password.",
+	})
+}
+
+// setPassword hashes and stores a new password along with any extra column
+// updates, and revokes every refresh token the user holds
+func (h *AuthHandler) setPassword(tx *gorm.DB, user *models.User, newPassword string, updates map[string]interface{}) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	updates["password_hash"] = string(hashedPassword)
+	if err := tx.Model(user).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	return revokeUserRefreshTokens(tx, user.UserID, time.Now())
+}
+
+// sendPasswordResetEmail issues a reset token for the user and mails the link
+func (h *AuthHandler) sendPasswordResetEmail(ctx context.Context, user *models.User) error {
+	token, err := issueAccountToken(h.db, user.UserID, models.TokenPurposePasswordReset, h.authConfig.PasswordResetExpiry)
+	if err != nil {
+		return err
+	}
+
+	link := h.authConfig.AppBaseURL + "/reset-password?token=" + url.QueryEscape(token)
+
+	ctx, cancel := context.WithTimeout(ctx, mailTimeout)
+	defer cancel()
+
+	return h.mailer.Send(ctx, mailer.PasswordResetEmail(user.Email, user.Username, link, h.authConfig.PasswordResetExpiry))
+}
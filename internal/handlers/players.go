@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/config"
+	"github.com/1shoukr/swiftplay-backend/internal/database"
+	"github.com/1shoukr/swiftplay-backend/internal/games"
+	"github.com/1shoukr/swiftplay-backend/internal/matches"
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"github.com/1shoukr/swiftplay-backend/internal/validation"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type PlayerHandler struct {
+	db                *gorm.DB
+	catalog           *games.Catalog
+	matchmakingConfig *config.MatchmakingConfig
+}
+
+func NewPlayerHandler(db *database.Database, catalog *games.Catalog, matchmakingConfig *config.MatchmakingConfig) *PlayerHandler {
+	return &PlayerHandler{
+		db:                db.GetDB(),
+		catalog:           catalog,
+		matchmakingConfig: matchmakingConfig,
+	}
+}
+
+// playerSearchQuery lists the filters accepted by SearchPlayers
+type playerSearchQuery struct {
+	Game    string `form:"game"`
+	MinRank string `form:"min_rank"`
+	MaxRank string `form:"max_rank"`
+	Country string `form:"country"`
+	City    string `form:"city"`
+	Gender  string `form:"gender"`
+	MinAge  *int   `form:"min_age"`
+	MaxAge  *int   `form:"max_age"`
+	Limit   int    `form:"limit"`
+	Cursor  string `form:"cursor"`
+}
+
+// playerRow is a profile joined with its owner's username
+type playerRow struct {
+	models.Profile `gorm:"embedded"`
+	Username       string
+}
+
+// publicProfile is the view of a player shown to other players. Fields hidden
+// by the owner's privacy settings are omitted.
+type publicProfile struct {
+	Username  string            `json:"username"`
+	FirstName string            `json:"first_name,omitempty"`
+	LastName  string            `json:"last_name,omitempty"`
+	Gender    string            `json:"gender,omitempty"`
+	Age       *int              `json:"age,omitempty"`
+	Bio       string            `json:"bio,omitempty"`
+	City      string            `json:"city,omitempty"`
+	Country   string            `json:"country,omitempty"`
+	GameRanks map[string]string `json:"game_ranks"`
+	IsMatch   bool              `json:"is_match"`
+}
+
+// GetPlayer returns the public profile of the player with the given username.
+// Players blocked in either direction are not found.
+func (h *PlayerHandler) GetPlayer(c *gin.Context) {
+	viewerID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	owner, err := findVisiblePlayer(h.db, viewerID, strings.TrimSpace(c.Param("username")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load player",
+		})
+		return
+	}
+	if owner == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Player not found",
+		})
+		return
+	}
+
+	var profiles []models.Profile
+	if err := h.db.Where("user_id = ?", owner.UserID).Limit(1).Find(&profiles).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load player",
+		})
+		return
+	}
+
+	matched := false
+	if viewerID != owner.UserID {
+		matched, err = hasAcceptedMatch(h.db, viewerID, owner.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to load player",
+			})
+			return
+		}
+	}
+
+	var profile *models.Profile
+	if len(profiles) > 0 {
+		profile = &profiles[0]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"player": projectProfile(owner, profile, viewerID == owner.UserID, matched, time.Now()),
+	})
+}
+
+// SearchPlayers finds other players by game, rank range, location, age and gender
+// among those matches.Discoverable allows. Location, gender and age filters only
+// match players who made that field public.
+func (h *PlayerHandler) SearchPlayers(c *gin.Context) {
+	viewerID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	var params playerSearchQuery
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	fieldErrors := validation.FieldErrors{}
+	afterID, err := decodeCursor(params.Cursor)
+	if err != nil {
+		fieldErrors.Add("cursor", err.Error())
+	}
+
+	now := time.Now()
+	query := matches.Discoverable(h.db, viewerID, now.Add(-h.matchmakingConfig.PassCooldown)).
+		Select("profiles.*, users.username")
+
+	if params.Game != "" {
+		game, ok := h.catalog.Game(params.Game)
+		if !ok {
+			fieldErrors.Add("game", "game is not supported, see /api/games")
+		} else {
+			// gorm treats every ? as a bind parameter, so the jsonb key-exists operator
+			// (served by the GIN index) gets the catalog id inlined. Catalog ids are
+			// trusted configuration, and quotes are escaped regardless.
+			query = query.Where(fmt.Sprintf("profiles.game_ranks ? '%s'", strings.ReplaceAll(game.ID, "'", "''")))
+
+			rankValue := "(profiles.game_rank_values ->> '" + strings.ReplaceAll(game.ID, "'", "''") + "')::int"
+			if params.MinRank != "" {
+				if rank, ok := game.Rank(params.MinRank); ok {
+					query = query.Where(rankValue+" >= ?", rank.Value)
+				} else {
+					fieldErrors.Add("min_rank", fmt.Sprintf("rank is not valid for %s", game.Name))
+				}
+			}
+			if params.MaxRank != "" {
+				if rank, ok := game.Rank(params.MaxRank); ok {
+					query = query.Where(rankValue+" <= ?", rank.Value)
+				} else {
+					fieldErrors.Add("max_rank", fmt.Sprintf("rank is not valid for %s", game.Name))
+				}
+			}
+		}
+	} else if params.MinRank != "" || params.MaxRank != "" {
+		fieldErrors.Add("game", "game is required when filtering by rank")
+	}
+
+	if country := strings.TrimSpace(params.Country); country != "" {
+		query = query.Where("LOWER(profiles.country) = LOWER(?) AND profiles.privacy_country = ?", country, models.VisibilityPublic)
+	}
+	if city := strings.TrimSpace(params.City); city != "" {
+		query = query.Where("LOWER(profiles.city) = LOWER(?) AND profiles.privacy_city = ?", city, models.VisibilityPublic)
+	}
+	if gender := strings.TrimSpace(params.Gender); gender != "" {
+		query = query.Where("LOWER(profiles.gender) = LOWER(?) AND profiles.privacy_gender = ?", gender, models.VisibilityPublic)
+	}
+
+	if params.MinAge != nil && (*params.MinAge < 13 || *params.MinAge > 120) {
+		fieldErrors.Add("min_age", "must be between 13 and 120")
+	}
+	if params.MaxAge != nil && (*params.MaxAge < 13 || *params.MaxAge > 120) {
+		fieldErrors.Add("max_age", "must be between 13 and 120")
+	}
+	if params.MinAge != nil && params.MaxAge != nil && *params.MinAge > *params.MaxAge {
+		fieldErrors.Add("max_age", "must not be less than min_age")
+	}
+	if params.MinAge != nil || params.MaxAge != nil {
+		query = query.Where("profiles.date_of_birth IS NOT NULL AND profiles.privacy_age = ?", models.VisibilityPublic)
+	}
+	if params.MinAge != nil {
+		query = query.Where("profiles.date_of_birth <= ?", now.AddDate(-*params.MinAge, 0, 0))
+	}
+	if params.MaxAge != nil {
+		query = query.Where("profiles.date_of_birth > ?", now.AddDate(-(*params.MaxAge+1), 0, 0))
+	}
+
+	if fieldErrors.HasErrors() {
+		fieldErrors.Respond(c)
+		return
+	}
+
+	if afterID != 0 {
+		query = query.Where("profiles.user_id < ?", afterID)
+	}
+
+	limit := pageLimit(params.Limit, 20, 50)
+	var rows []playerRow
+	if err := query.Order("profiles.user_id DESC").Limit(limit + 1).Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to search players",
+		})
+		return
+	}
+
+	var nextCursor *string
+	if len(rows) > limit {
+		rows = rows[:limit]
+		cursor := encodeCursor(rows[len(rows)-1].UserID)
+		nextCursor = &cursor
+	}
+
+	players := make([]publicProfile, 0, len(rows))
+	for i := range rows {
+		owner := models.User{UserID: rows[i].UserID, Username: rows[i].Username}
+		players = append(players, projectProfile(&owner, &rows[i].Profile, false, false, now))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"players":     players,
+		"next_cursor": nextCursor,
+	})
+}
+
+// projectProfile builds the public view of a player. The owner sees every
+// field; other viewers see fields according to the owner's privacy settings.
+func projectProfile(owner *models.User, profile *models.Profile, self, matched bool, now time.Time) publicProfile {
+	view := publicProfile{
+		Username:  owner.Username,
+		GameRanks: map[string]string{},
+		IsMatch:   matched,
+	}
+	if profile == nil {
+		return view
+	}
+
+	visible := func(setting string) bool {
+		switch setting {
+		case models.VisibilityPublic:
+			return true
+		case models.VisibilityMatches:
+			return self || matched
+		default:
+			return self
+		}
+	}
+
+	privacy := profile.Privacy
+	if visible(privacy.FirstName) && profile.FirstName != nil {
+		view.FirstName = *profile.FirstName
+	}
+	if visible(privacy.LastName) && profile.LastName != nil {
+		view.LastName = *profile.LastName
+	}
+	if visible(privacy.Gender) && profile.Gender != nil {
+		view.Gender = *profile.Gender
+	}
+	if visible(privacy.Age) && profile.DateOfBirth != nil {
+		age := ageOn(*profile.DateOfBirth, now)
+		view.Age = &age
+	}
+	if visible(privacy.City) && profile.City != nil {
+		view.City = *profile.City
+	}
+	if visible(privacy.Country) && profile.Country != nil {
+		view.Country = *profile.Country
+	}
+	if profile.Bio != nil {
+		view.Bio = *profile.Bio
+	}
+	if profile.GameRanks != nil {
+		view.GameRanks = profile.GameRanks
+	}
+	return view
+}
+
+// hasAcceptedMatch reports whether the two users have an accepted match in either direction
+func hasAcceptedMatch(db *gorm.DB, a, b uint) (bool, error) {
+	var count int64
+	err := db.Model(&models.Match{}).
+		Where("status = ?", models.MatchStatusAccepted).
+		Where("(user_id_1 = ? AND user_id_2 = ?) OR (user_id_1 = ? AND user_id_2 = ?)", a, b, b, a).
+		Count(&count).Error
+	return count > 0, err
+}
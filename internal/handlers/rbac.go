@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/1shoukr/swiftplay-backend/internal/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+type RBACHandler struct {
+	policy *rbac.Policy
+}
+
+func NewRBACHandler(policy *rbac.Policy) *RBACHandler {
+	return &RBACHandler{policy: policy}
+}
+
+// PermissionMatrix returns every role with the permissions it effectively holds
+func (h *RBACHandler) PermissionMatrix(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"hierarchy": h.policy.Roles(),
+		"roles":     h.policy.Matrix(),
+	})
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+
+	"github.com/1shoukr/swiftplay-backend/internal/chat"
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/ratelimit"
+	"github.com/1shoukr/swiftplay-backend/internal/realtime"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// Requests a player can send over their WebSocket connection
+const (
+	inboundMessage = "message"
+	inboundTyping  = "typing"
+	inboundRead    = "read"
+)
+
+type RealtimeHandler struct {
+	hub          *realtime.Hub
+	chat         *chat.Service
+	messageLimit *ratelimit.Limiter
+	upgrader     websocket.Upgrader
+}
+
+func NewRealtimeHandler(hub *realtime.Hub, chatService *chat.Service, messageLimit *ratelimit.Limiter) *RealtimeHandler {
+	return &RealtimeHandler{
+		hub:          hub,
+		chat:         chatService,
+		messageLimit: messageLimit,
+		// The default origin check accepts the mobile app, which sends no Origin,
+		// and keeps browsers to same-origin pages
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+		},
+	}
+}
+
+// Connect upgrades the request to a WebSocket that receives chat events for all
+// of the caller's matches. Clients authenticate with the usual Authorization
+// header during the handshake.
+func (h *RealtimeHandler) Connect(c *gin.Context) {
+	userID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	// Upgrade writes its own error response when the handshake is invalid
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+
+	h.hub.Serve(conn, userID, h.handleInbound)
+}
+
+// handleInbound dispatches a request received over a player's connection
+func (h *RealtimeHandler) handleInbound(ctx context.Context, userID uint, inbound realtime.Inbound) error {
+	if inbound.MatchID == 0 {
+		return errors.New("match_id is required")
+	}
+
+	switch inbound.Type {
+	case inboundMessage:
+		// Messages sent here count against the same limit as POST /messages
+		result, err := h.messageLimit.Take(ctx, ratelimit.UserKey(userID))
+		if err != nil {
+			log.Printf("Failed to check message rate limit for user %d: %v", userID, err)
+		} else if !result.Allowed {
+			return fmt.Errorf("too many messages, try again in %d seconds", int(math.Ceil(result.RetryAfter.Seconds())))
+		}
+		_, err = h.chat.Send(ctx, inbound.MatchID, userID, inbound.Content)
+		return realtimeError(err, "failed to send message")
+	case inboundTyping:
+		return realtimeError(h.chat.Typing(ctx, inbound.MatchID, userID), "failed to send typing indicator")
+	case inboundRead:
+		if inbound.UpToMessageID == 0 {
+			return errors.New("up_to_message_id is required")
+		}
+		_, _, err := h.chat.MarkRead(ctx, inbound.MatchID, userID, inbound.UpToMessageID)
+		return realtimeError(err, "failed to mark messages as read")
+	default:
+		return errors.New("type must be one of message, typing, read")
+	}
+}
+
+// realtimeError keeps the chat service's validation errors and hides the details
+// of unexpected failures behind fallback
+func realtimeError(err error, fallback string) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, chat.ErrMatchNotFound), errors.Is(err, chat.ErrMatchNotActive), errors.Is(err, chat.ErrInvalidContent):
+		return err
+	default:
+		return errors.New(fallback)
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"github.com/1shoukr/swiftplay-backend/internal/recommendations"
+	"github.com/1shoukr/swiftplay-backend/internal/validation"
+	"github.com/gin-gonic/gin"
+)
+
+type RecommendationHandler struct {
+	recommendations *recommendations.Service
+}
+
+func NewRecommendationHandler(recommendationService *recommendations.Service) *RecommendationHandler {
+	return &RecommendationHandler{recommendations: recommendationService}
+}
+
+// recommendedPlayer is a feed entry: the player's public profile and how well they fit
+type recommendedPlayer struct {
+	publicProfile
+	Score float64 `json:"score"`
+}
+
+// GetFeed returns the caller's recommended players, best match first
+func (h *RecommendationHandler) GetFeed(c *gin.Context) {
+	userID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	var params struct {
+		Limit  int    `form:"limit"`
+		Cursor string `form:"cursor"`
+	}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	afterPosition, err := decodeCursor(params.Cursor)
+	if err != nil {
+		fieldErrors := validation.FieldErrors{}
+		fieldErrors.Add("cursor", err.Error())
+		fieldErrors.Respond(c)
+		return
+	}
+
+	limit := pageLimit(params.Limit, 20, 50)
+	entries, err := h.recommendations.Feed(c.Request.Context(), userID, int(afterPosition), limit+1)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load recommendations",
+		})
+		return
+	}
+
+	var nextCursor *string
+	if len(entries) > limit {
+		entries = entries[:limit]
+		cursor := encodeCursor(uint(entries[len(entries)-1].Position))
+		nextCursor = &cursor
+	}
+
+	now := time.Now()
+	players := make([]recommendedPlayer, 0, len(entries))
+	for i := range entries {
+		owner := models.User{UserID: entries[i].UserID, Username: entries[i].Username}
+		players = append(players, recommendedPlayer{
+			publicProfile: projectProfile(&owner, &entries[i].Profile, false, false, now),
+			Score:         entries[i].Score,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"players":     players,
+		"next_cursor": nextCursor,
+	})
+}
@@ -0,0 +1,448 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/1shoukr/swiftplay-backend/internal/database"
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"github.com/1shoukr/swiftplay-backend/internal/validation"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxReportDetails is the longest explanation, in characters, a report can carry
+	maxReportDetails = 1000
+	// maxReportEvidence caps how many messages can be attached to a report
+	maxReportEvidence = 20
+)
+
+type ReportHandler struct {
+	db *gorm.DB
+}
+
+func NewReportHandler(db *database.Database) *ReportHandler {
+	return &ReportHandler{db: db.GetDB()}
+}
+
+// reportView is a report as shown in the moderation queue
+type reportView struct {
+	models.Report
+	ReporterUsername string `json:"reporter_username"`
+	ReportedUsername string `json:"reported_username"`
+}
+
+// CreateReport files a report about another player into the moderation queue.
+// Messages from the caller's conversation with the player can be attached as
+// evidence; they are copied into the report.
+func (h *ReportHandler) CreateReport(c *gin.Context) {
+	userID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	var requestData struct {
+		Username   string `json:"username"`
+		Reason     string `json:"reason"`
+		Details    string `json:"details"`
+		MessageIDs []uint `json:"message_ids"`
+	}
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	fieldErrors := validation.FieldErrors{}
+	username := strings.TrimSpace(requestData.Username)
+	if username == "" {
+		fieldErrors.Add("username", "username is required")
+	}
+	if !isReportReason(requestData.Reason) {
+		fieldErrors.Add("reason", "must be one of "+strings.Join(models.ReportReasons(), ", "))
+	}
+	details := strings.TrimSpace(requestData.Details)
+	if utf8.RuneCountInString(details) > maxReportDetails {
+		fieldErrors.Add("details", "must be at most 1000 characters")
+	}
+	if details == "" && requestData.Reason == models.ReportReasonOther {
+		fieldErrors.Add("details", "details are required when the reason is other")
+	}
+	messageIDs := uniqueIDs(requestData.MessageIDs)
+	if len(messageIDs) > maxReportEvidence {
+		fieldErrors.Add("message_ids", "at most 20 messages can be attached")
+	}
+	if fieldErrors.HasErrors() {
+		fieldErrors.Respond(c)
+		return
+	}
+
+	reported, err := findPlayer(h.db, username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load player",
+		})
+		return
+	}
+	if reported == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Player not found",
+		})
+		return
+	}
+	if reported.UserID == userID {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "You cannot report yourself",
+		})
+		return
+	}
+
+	var openReports int64
+	if err := h.db.Model(&models.Report{}).
+		Where("reporter_id = ? AND reported_id = ? AND status = ?", userID, reported.UserID, models.ReportStatusOpen).
+		Count(&openReports).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to file report",
+		})
+		return
+	}
+	if openReports > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "You already have an open report about this player",
+		})
+		return
+	}
+
+	evidence := []models.ReportEvidence{}
+	if len(messageIDs) > 0 {
+		userID1, userID2 := models.MatchPair(userID, reported.UserID)
+		var messages []models.Message
+		if err := h.db.Where("message_id IN ?", messageIDs).
+			Where("match_id IN (SELECT match_id FROM matches WHERE user_id_1 = ? AND user_id_2 = ?)", userID1, userID2).
+			Order("message_id").
+			Find(&messages).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to file report",
+			})
+			return
+		}
+		if len(messages) != len(messageIDs) {
+			fieldErrors.Add("message_ids", "must be messages from your conversation with this player")
+			fieldErrors.Respond(c)
+			return
+		}
+		for _, message := range messages {
+			evidence = append(evidence, models.ReportEvidence{
+				MessageID: message.MessageID,
+				MatchID:   message.MatchID,
+				SenderID:  message.SenderID,
+				Content:   message.Content,
+				SentAt:    message.CreatedAt,
+			})
+		}
+	}
+
+	report := models.Report{
+		ReporterID: userID,
+		ReportedID: reported.UserID,
+		Reason:     requestData.Reason,
+		Details:    details,
+		Evidence:   evidence,
+		Status:     models.ReportStatusOpen,
+	}
+	if err := h.db.Create(&report).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to file report",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Report submitted",
+		"report": gin.H{
+			"report_id":  report.ReportID,
+			"reason":     report.Reason,
+			"status":     report.Status,
+			"created_at": report.CreatedAt,
+		},
+	})
+}
+
+// ListReports returns the moderation queue. Open reports come oldest first so
+// they are worked through in order; reviewed ones come newest first.
+func (h *ReportHandler) ListReports(c *gin.Context) {
+	var params struct {
+		Status     string `form:"status"`
+		Reason     string `form:"reason"`
+		ReportedID uint   `form:"reported_id"`
+		Limit      int    `form:"limit"`
+		Cursor     string `form:"cursor"`
+	}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if params.Status == "" {
+		params.Status = models.ReportStatusOpen
+	}
+
+	fieldErrors := validation.FieldErrors{}
+	if !isReportStatus(params.Status) {
+		fieldErrors.Add("status", "must be one of open, resolved, dismissed")
+	}
+	if params.Reason != "" && !isReportReason(params.Reason) {
+		fieldErrors.Add("reason", "must be one of "+strings.Join(models.ReportReasons(), ", "))
+	}
+	cursorID, err := decodeCursor(params.Cursor)
+	if err != nil {
+		fieldErrors.Add("cursor", err.Error())
+	}
+	if fieldErrors.HasErrors() {
+		fieldErrors.Respond(c)
+		return
+	}
+
+	query := h.db.Where("status = ?", params.Status)
+	if params.Reason != "" {
+		query = query.Where("reason = ?", params.Reason)
+	}
+	if params.ReportedID != 0 {
+		query = query.Where("reported_id = ?", params.ReportedID)
+	}
+
+	oldestFirst := params.Status == models.ReportStatusOpen
+	if cursorID != 0 {
+		if oldestFirst {
+			query = query.Where("report_id > ?", cursorID)
+		} else {
+			query = query.Where("report_id < ?", cursorID)
+		}
+	}
+	if oldestFirst {
+		query = query.Order("report_id")
+	} else {
+		query = query.Order("report_id DESC")
+	}
+
+	limit := pageLimit(params.Limit, 20, 100)
+	var reports []models.Report
+	if err := query.Limit(limit + 1).Find(&reports).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load reports",
+		})
+		return
+	}
+
+	var nextCursor *string
+	if len(reports) > limit {
+		reports = reports[:limit]
+		cursor := encodeCursor(reports[len(reports)-1].ReportID)
+		nextCursor = &cursor
+	}
+
+	views, err := reportViews(h.db, reports)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load reports",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reports":     views,
+		"next_cursor": nextCursor,
+	})
+}
+
+// GetReport returns a single report with its evidence
+func (h *ReportHandler) GetReport(c *gin.Context) {
+	report, ok := h.report(c)
+	if !ok {
+		return
+	}
+
+	views, err := reportViews(h.db, []models.Report{*report})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load report",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"report": views[0],
+	})
+}
+
+// ReviewReport closes an open report as resolved or dismissed, recording the
+// reviewing admin and their notes
+func (h *ReportHandler) ReviewReport(c *gin.Context) {
+	reviewerID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	var requestData struct {
+		Status     string `json:"status"`
+		Resolution string `json:"resolution"`
+	}
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	fieldErrors := validation.FieldErrors{}
+	if requestData.Status != models.ReportStatusResolved && requestData.Status != models.ReportStatusDismissed {
+		fieldErrors.Add("status", "must be resolved or dismissed")
+	}
+	resolution := strings.TrimSpace(requestData.Resolution)
+	if utf8.RuneCountInString(resolution) > maxReportDetails {
+		fieldErrors.Add("resolution", "must be at most 1000 characters")
+	}
+	if fieldErrors.HasErrors() {
+		fieldErrors.Respond(c)
+		return
+	}
+
+	report, ok := h.report(c)
+	if !ok {
+		return
+	}
+
+	reviewedAt := time.Now()
+	result := h.db.Model(&models.Report{}).
+		Where("report_id = ? AND status = ?", report.ReportID, models.ReportStatusOpen).
+		Updates(map[string]interface{}{
+			"status":      requestData.Status,
+			"resolution":  resolution,
+			"reviewer_id": reviewerID,
+			"reviewed_at": reviewedAt,
+		})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to review report",
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Report has already been reviewed",
+		})
+		return
+	}
+
+	report.Status = requestData.Status
+	report.Resolution = resolution
+	report.ReviewerID = &reviewerID
+	report.ReviewedAt = &reviewedAt
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Report reviewed",
+		"report":  report,
+	})
+}
+
+// report loads the report named in the URL. It writes the error response itself
+// and reports whether the request may continue.
+func (h *ReportHandler) report(c *gin.Context) (*models.Report, bool) {
+	reportID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid report ID",
+		})
+		return nil, false
+	}
+
+	var reports []models.Report
+	if err := h.db.Where("report_id = ?", reportID).Limit(1).Find(&reports).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load report",
+		})
+		return nil, false
+	}
+	if len(reports) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Report not found",
+		})
+		return nil, false
+	}
+	return &reports[0], true
+}
+
+// reportViews attaches the usernames of both players to each report. Accounts
+// that were deleted since are still named.
+func reportViews(db *gorm.DB, reports []models.Report) ([]reportView, error) {
+	userIDs := make([]uint, 0, len(reports)*2)
+	for _, report := range reports {
+		userIDs = append(userIDs, report.ReporterID, report.ReportedID)
+	}
+
+	usernames := make(map[uint]string, len(userIDs))
+	if len(userIDs) > 0 {
+		var users []models.User
+		if err := db.Unscoped().Select("user_id", "username").
+			Where("user_id IN ?", userIDs).Find(&users).Error; err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			usernames[user.UserID] = user.Username
+		}
+	}
+
+	views := make([]reportView, 0, len(reports))
+	for _, report := range reports {
+		views = append(views, reportView{
+			Report:           report,
+			ReporterUsername: usernames[report.ReporterID],
+			ReportedUsername: usernames[report.ReportedID],
+		})
+	}
+	return views, nil
+}
+
+func isReportReason(reason string) bool {
+	for _, r := range models.ReportReasons() {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+func isReportStatus(status string) bool {
+	return status == models.ReportStatusOpen || status == models.ReportStatusResolved || status == models.ReportStatusDismissed
+}
+
+// uniqueIDs drops zero and repeated ids, keeping the first occurrence of each
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
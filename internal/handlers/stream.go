@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/realtime"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+type StreamHandler struct {
+	stream    *realtime.Stream
+	keepAlive time.Duration
+}
+
+func NewStreamHandler(stream *realtime.Stream, keepAlive time.Duration) *StreamHandler {
+	return &StreamHandler{
+		stream:    stream,
+		keepAlive: keepAlive,
+	}
+}
+
+// Stream sends the caller's chat and match events as Server-Sent Events. Clients
+// that reconnect with Last-Event-ID receive the events they missed, or a reset
+// event when those are no longer available.
+func (h *StreamHandler) Stream(c *gin.Context) {
+	userID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	subscription := h.stream.Subscribe(userID)
+	defer subscription.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Keep reverse proxies from buffering the stream
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	events, cursor, complete := h.stream.Since(userID, c.GetHeader("Last-Event-ID"))
+	h.write(c, events, cursor, complete)
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(h.keepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-subscription.Done():
+			return
+		case <-subscription.C:
+			events, cursor, complete = h.stream.Since(userID, cursor)
+			h.write(c, events, cursor, complete)
+		case <-keepAlive.C:
+			// Comment lines are ignored by clients but keep idle proxies from
+			// closing the connection
+			if _, err := c.Writer.WriteString(": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+// write sends the events, preceded by a reset when the client missed some.
+// The reset carries the cursor so a reconnect resumes after it.
+func (h *StreamHandler) write(c *gin.Context, events []realtime.StreamEvent, cursor string, complete bool) {
+	if !complete {
+		c.Render(-1, sse.Event{
+			Id:    cursor,
+			Event: realtime.EventReset,
+			Data:  realtime.Event{Type: realtime.EventReset},
+		})
+	}
+	for _, event := range events {
+		c.Render(-1, sse.Event{
+			Id:    event.ID,
+			Event: event.Event.Type,
+			Data:  event.Event,
+		})
+	}
+}
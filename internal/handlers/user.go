@@ -1,28 +1,97 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
 	"net/http"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/1shoukr/swiftplay-backend/internal/config"
 	"github.com/1shoukr/swiftplay-backend/internal/database"
+	"github.com/1shoukr/swiftplay-backend/internal/games"
+	"github.com/1shoukr/swiftplay-backend/internal/jwt"
+	"github.com/1shoukr/swiftplay-backend/internal/mailer"
 	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"github.com/1shoukr/swiftplay-backend/internal/password"
+	"github.com/1shoukr/swiftplay-backend/internal/scoring"
+	"github.com/1shoukr/swiftplay-backend/internal/userstate"
+	"github.com/1shoukr/swiftplay-backend/internal/validation"
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// registerUserRequest is the registration payload. Only the fields listed here can
+// be set by clients; server-controlled columns such as auth_level are never bound.
+type registerUserRequest struct {
+	User struct {
+		Username string `json:"username"`
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	} `json:"user"`
+	Profile profileRequest `json:"profile"`
+}
+
+// profileRequest lists the profile fields a user is allowed to set
+type profileRequest struct {
+	FirstName    *string           `json:"first_name"`
+	LastName     *string           `json:"last_name"`
+	Gender       *string           `json:"gender"`
+	DateOfBirth  *time.Time        `json:"date_of_birth"`
+	Bio          *string           `json:"bio"`
+	City         *string           `json:"city"`
+	Country      *string           `json:"country"`
+	GameRanks    map[string]string `json:"game_ranks"`
+	Latitude     *float64          `json:"latitude"`
+	Longitude    *float64          `json:"longitude"`
+	Availability []string          `json:"availability"`
+	Privacy      *privacyRequest   `json:"privacy"`
+
+	// resolvedRanks and rankValues hold GameRanks mapped onto the game catalog by validate
+	resolvedRanks map[string]string
+	rankValues    map[string]int
+}
+
+// privacyRequest sets the visibility of individual profile fields
+type privacyRequest struct {
+	FirstName *string `json:"first_name"`
+	LastName  *string `json:"last_name"`
+	Gender    *string `json:"gender"`
+	Age       *string `json:"age"`
+	City      *string `json:"city"`
+	Country   *string `json:"country"`
+}
+
 type UserHandler struct {
-	db *gorm.DB
+	db         *gorm.DB
+	jwtService *jwt.JWTService
+	mailer     mailer.Mailer
+	userStates *userstate.Cache
+	catalog    *games.Catalog
+	authConfig *config.AuthConfig
 }
 
-func NewUserHandler(db *database.Database) *UserHandler {
-	return &UserHandler{db: db.GetDB()}
+func NewUserHandler(db *database.Database, jwtService *jwt.JWTService, mailer mailer.Mailer, userStates *userstate.Cache, catalog *games.Catalog, authConfig *config.AuthConfig) *UserHandler {
+	return &UserHandler{
+		db:         db.GetDB(),
+		jwtService: jwtService,
+		mailer:     mailer,
+		userStates: userStates,
+		catalog:    catalog,
+		authConfig: authConfig,
+	}
 }
 
 func (h *UserHandler) CreateUser(c *gin.Context) {
-	var requestData struct {
-		User    models.User    `json:"user"`
-		Profile models.Profile `json:"profile"`
-	}
+	var requestData registerUserRequest
 
 	if err := c.ShouldBindJSON(&requestData); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -31,23 +100,52 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		})
 		return
 	}
-	
-	if requestData.User.Username == "" || requestData.User.Email == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Username and email are required",
+
+	requestData.User.Username = strings.TrimSpace(requestData.User.Username)
+	requestData.User.Email = strings.TrimSpace(requestData.User.Email)
+
+	fieldErrors := validation.FieldErrors{}
+	validateUsername(fieldErrors, "user.username", requestData.User.Username)
+	validateEmail(fieldErrors, "user.email", requestData.User.Email)
+	if err := password.Validate(requestData.User.Password, requestData.User.Username, requestData.User.Email); err != nil {
+		fieldErrors.Add("user.password", err.Error())
+	}
+	requestData.Profile.validate(fieldErrors, "profile", h.catalog)
+
+	if fieldErrors.HasErrors() {
+		fieldErrors.Respond(c)
+		return
+	}
+
+	if conflicts, err := h.findRegistrationConflicts(requestData.User.Username, requestData.User.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to check existing users",
+		})
+		return
+	} else if conflicts.HasErrors() {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":  "User already exists",
+			"fields": conflicts,
 		})
 		return
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(requestData.User.PasswordHash), bcrypt.DefaultCost)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(requestData.User.Password), bcrypt.DefaultCost)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to hash password",
 		})
+		return
 	}
-	requestData.User.PasswordHash = string(hashedPassword)
-	requestData.User.AuthLevel = "user"
 
+	user := models.User{
+		Username:     requestData.User.Username,
+		Email:        requestData.User.Email,
+		PasswordHash: string(hashedPassword),
+		AuthLevel:    "user",
+	}
+	profile := models.Profile{Privacy: models.DefaultProfilePrivacy()}
+	requestData.Profile.applyTo(&profile)
 
 	tx := h.db.Begin()
 	defer func() {
@@ -56,7 +154,7 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		}
 	}()
 
-	if err := tx.Create(&requestData.User).Error; err != nil {
+	if err := tx.Create(&user).Error; err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to create user",
@@ -65,13 +163,14 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		return
 	}
 
-	requestData.Profile.UserID = requestData.User.UserID
+	profile.UserID = user.UserID
 
-	if requestData.Profile.GameRanks == nil {
-		requestData.Profile.GameRanks = make(map[string]string)
+	if profile.GameRanks == nil {
+		profile.GameRanks = make(map[string]string)
+		profile.GameRankValues = make(map[string]int)
 	}
 
-	if err := tx.Create(&requestData.Profile).Error; err != nil {
+	if err := tx.Create(&profile).Error; err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to create profile",
@@ -87,10 +186,256 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		return
 	}
 
+	// The account exists either way; a failed email can be retried via resend-verification
+	if err := sendVerificationEmail(c.Request.Context(), h.db, h.mailer, h.authConfig, &user); err != nil {
+		log.Printf("Failed to send verification email to user %d: %v", user.UserID, err)
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
-		"message":         "User and gaming profile created successfully",
-		"user":            requestData.User,
-		"profile":         requestData.Profile,
+		"message":      "User and gaming profile created successfully",
+		"user":         user,
+		"profile":      profile,
 		"linked_games": []string{"valorant"}, // we can add the user's linked games here
 	})
 }
+
+// findRegistrationConflicts reports which of the username and email are already taken
+func (h *UserHandler) findRegistrationConflicts(username, email string) (validation.FieldErrors, error) {
+	conflicts := validation.FieldErrors{}
+
+	taken, err := usernameTaken(h.db, username, 0)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		conflicts.Add("user.username", "username is already taken")
+	}
+
+	var count int64
+	if err := h.db.Unscoped().Model(&models.User{}).Where("LOWER(email) = LOWER(?)", email).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		conflicts.Add("user.email", "email is already registered")
+	}
+
+	return conflicts, nil
+}
+
+// usernameTaken reports whether any account other than exceptUserID, including
+// deleted accounts still in their grace period, uses the username
+func usernameTaken(db *gorm.DB, username string, exceptUserID uint) (bool, error) {
+	var count int64
+	err := db.Unscoped().Model(&models.User{}).
+		Where("LOWER(username) = LOWER(?) AND user_id <> ?", username, exceptUserID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func validateUsername(errs validation.FieldErrors, field, username string) {
+	length := utf8.RuneCountInString(username)
+	switch {
+	case username == "":
+		errs.Add(field, "username is required")
+	case length < 3 || length > 50:
+		errs.Add(field, "username must be between 3 and 50 characters")
+	case !usernamePattern.MatchString(username):
+		errs.Add(field, "username may only contain letters, numbers, '.', '_' and '-'")
+	}
+}
+
+func validateEmail(errs validation.FieldErrors, field, email string) {
+	if email == "" {
+		errs.Add(field, "email is required")
+		return
+	}
+
+	if len(email) > 100 {
+		errs.Add(field, "email must be at most 100 characters")
+		return
+	}
+
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		errs.Add(field, "email must be a valid email address")
+	}
+}
+
+// validate checks every supplied profile field; nil fields are left untouched
+func (p *profileRequest) validate(errs validation.FieldErrors, prefix string, catalog *games.Catalog) {
+	validateOptionalString(errs, prefix+".first_name", p.FirstName, 50)
+	validateOptionalString(errs, prefix+".last_name", p.LastName, 50)
+	validateOptionalString(errs, prefix+".gender", p.Gender, 20)
+	validateOptionalString(errs, prefix+".bio", p.Bio, 1000)
+	validateOptionalString(errs, prefix+".city", p.City, 100)
+	validateOptionalString(errs, prefix+".country", p.Country, 100)
+
+	if p.DateOfBirth != nil {
+		age := ageOn(*p.DateOfBirth, time.Now())
+		switch {
+		case p.DateOfBirth.After(time.Now()):
+			errs.Add(prefix+".date_of_birth", "date of birth must be in the past")
+		case age < 13:
+			errs.Add(prefix+".date_of_birth", "you must be at least 13 years old")
+		case age > 120:
+			errs.Add(prefix+".date_of_birth", "date of birth is not valid")
+		}
+	}
+
+	if (p.Latitude == nil) != (p.Longitude == nil) {
+		errs.Add(prefix+".latitude", "latitude and longitude must be set together")
+	}
+	if p.Latitude != nil && (*p.Latitude < -90 || *p.Latitude > 90) {
+		errs.Add(prefix+".latitude", "latitude must be between -90 and 90")
+	}
+	if p.Longitude != nil && (*p.Longitude < -180 || *p.Longitude > 180) {
+		errs.Add(prefix+".longitude", "longitude must be between -180 and 180")
+	}
+
+	for i, slot := range p.Availability {
+		if !scoring.IsAvailabilitySlot(slot) {
+			errs.Add(fmt.Sprintf("%s.availability.%d", prefix, i), "must be a day and time of day such as sat_evening")
+		}
+	}
+
+	if p.Privacy != nil {
+		p.Privacy.validate(errs, prefix+".privacy")
+	}
+
+	if len(p.GameRanks) > 20 {
+		errs.Add(prefix+".game_ranks", "at most 20 games can be listed")
+	}
+	if p.GameRanks == nil {
+		return
+	}
+	p.resolvedRanks = make(map[string]string, len(p.GameRanks))
+	p.rankValues = make(map[string]int, len(p.GameRanks))
+	for gameName, rankName := range p.GameRanks {
+		field := fmt.Sprintf("%s.game_ranks.%s", prefix, gameName)
+		if strings.TrimSpace(gameName) == "" || utf8.RuneCountInString(gameName) > 50 {
+			errs.Add(field, "game must be between 1 and 50 characters")
+			continue
+		}
+		if strings.TrimSpace(rankName) == "" || utf8.RuneCountInString(rankName) > 50 {
+			errs.Add(field, "rank must be between 1 and 50 characters")
+			continue
+		}
+
+		game, rank, err := catalog.ResolveRank(gameName, rankName)
+		switch {
+		case errors.Is(err, games.ErrUnknownGame):
+			errs.Add(field, "game is not supported, see /api/games")
+		case errors.Is(err, games.ErrUnknownRank):
+			errs.Add(field, fmt.Sprintf("rank is not valid for %s, see /api/games/%s", game.Name, game.ID))
+		case p.resolvedRanks[game.ID] != "":
+			errs.Add(field, fmt.Sprintf("%s is listed more than once", game.Name))
+		default:
+			p.resolvedRanks[game.ID] = rank.Name
+			p.rankValues[game.ID] = rank.Value
+		}
+	}
+}
+
+// applyTo copies every supplied field onto the profile
+func (p *profileRequest) applyTo(profile *models.Profile) {
+	if p.FirstName != nil {
+		profile.FirstName = trimmed(p.FirstName)
+	}
+	if p.LastName != nil {
+		profile.LastName = trimmed(p.LastName)
+	}
+	if p.Gender != nil {
+		profile.Gender = trimmed(p.Gender)
+	}
+	if p.DateOfBirth != nil {
+		profile.DateOfBirth = p.DateOfBirth
+	}
+	if p.Bio != nil {
+		profile.Bio = trimmed(p.Bio)
+	}
+	if p.City != nil {
+		profile.City = trimmed(p.City)
+	}
+	if p.Country != nil {
+		profile.Country = trimmed(p.Country)
+	}
+	if p.GameRanks != nil {
+		profile.GameRanks = p.resolvedRanks
+		profile.GameRankValues = p.rankValues
+	}
+	if p.Latitude != nil && p.Longitude != nil {
+		// Two decimals (about 1km) is plenty for matching and avoids storing exact locations
+		latitude := math.Round(*p.Latitude*100) / 100
+		longitude := math.Round(*p.Longitude*100) / 100
+		profile.Latitude = &latitude
+		profile.Longitude = &longitude
+	}
+	if p.Availability != nil {
+		seen := make(map[string]bool, len(p.Availability))
+		profile.Availability = make([]string, 0, len(p.Availability))
+		for _, slot := range p.Availability {
+			if !seen[slot] {
+				seen[slot] = true
+				profile.Availability = append(profile.Availability, slot)
+			}
+		}
+	}
+	if p.Privacy != nil {
+		p.Privacy.applyTo(&profile.Privacy)
+	}
+}
+
+func (p *privacyRequest) validate(errs validation.FieldErrors, prefix string) {
+	fields := map[string]*string{
+		"first_name": p.FirstName,
+		"last_name":  p.LastName,
+		"gender":     p.Gender,
+		"age":        p.Age,
+		"city":       p.City,
+		"country":    p.Country,
+	}
+	for name, value := range fields {
+		if value == nil {
+			continue
+		}
+		switch *value {
+		case models.VisibilityPublic, models.VisibilityMatches, models.VisibilityPrivate:
+		default:
+			errs.Add(prefix+"."+name, "must be one of public, matches or private")
+		}
+	}
+}
+
+func (p *privacyRequest) applyTo(privacy *models.ProfilePrivacy) {
+	setIfPresent := func(dst *string, value *string) {
+		if value != nil {
+			*dst = *value
+		}
+	}
+	setIfPresent(&privacy.FirstName, p.FirstName)
+	setIfPresent(&privacy.LastName, p.LastName)
+	setIfPresent(&privacy.Gender, p.Gender)
+	setIfPresent(&privacy.Age, p.Age)
+	setIfPresent(&privacy.City, p.City)
+	setIfPresent(&privacy.Country, p.Country)
+}
+
+func validateOptionalString(errs validation.FieldErrors, field string, value *string, maxLength int) {
+	if value != nil && utf8.RuneCountInString(strings.TrimSpace(*value)) > maxLength {
+		errs.Add(field, fmt.Sprintf("must be at most %d characters", maxLength))
+	}
+}
+
+func trimmed(value *string) *string {
+	s := strings.TrimSpace(*value)
+	return &s
+}
+
+// ageOn returns the age in whole years of someone born on dob at the given time
+func ageOn(dob, at time.Time) int {
+	age := at.Year() - dob.Year()
+	if at.Month() < dob.Month() || (at.Month() == dob.Month() && at.Day() < dob.Day()) {
+		age--
+	}
+	return age
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/config"
+	"github.com/1shoukr/swiftplay-backend/internal/mailer"
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// mailTimeout bounds how long a request waits for an email to be handed off
+const mailTimeout = 10 * time.Second
+
+// VerifyEmail consumes an email verification token and marks the address as verified
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var requestData struct {
+		Token string `json:"token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		token, err := consumeAccountToken(tx, requestData.Token, models.TokenPurposeEmailVerification)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&models.User{}).
+			Where("user_id = ? AND email_verified_at IS NULL", token.UserID).
+			Update("email_verified_at", time.Now()).Error
+	})
+
+	if errors.Is(err, errAccountTokenInvalid) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Verification token is invalid or has expired",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to verify email",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Email verified successfully",
+	})
+}
+
+// ResendVerification issues a fresh verification email to the caller
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	userID, _, _, exists := middleware.GetUserFromContext(c)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "User data not found in context",
+		})
+		return
+	}
+
+	var user models.User
+	if err := h.db.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "User not found",
+		})
+		return
+	}
+
+	if user.EmailVerifiedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Email is already verified",
+		})
+		return
+	}
+
+	if err := sendVerificationEmail(c.Request.Context(), h.db, h.mailer, h.authConfig, &user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to send verification email",
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Verification email sent",
+	})
+}
+
+// sendVerificationEmail issues a verification token for the user and mails the link
+func sendVerificationEmail(ctx context.Context, db *gorm.DB, m mailer.Mailer, cfg *config.AuthConfig, user *models.User) error {
+	token, err := issueAccountToken(db, user.UserID, models.TokenPurposeEmailVerification, cfg.EmailVerificationExpiry)
+	if err != nil {
+		return err
+	}
+
+	link := cfg.AppBaseURL + "/verify-email?token=" + url.QueryEscape(token)
+
+	ctx, cancel := context.WithTimeout(ctx, mailTimeout)
+	defer cancel()
+
+	return m.Send(ctx, mailer.VerificationEmail(user.Email, user.Username, link, cfg.EmailVerificationExpiry))
+}
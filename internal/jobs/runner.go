@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Runner runs background tasks on fixed intervals until it is closed
+type Runner struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewRunner creates a runner with no scheduled tasks
+func NewRunner() *Runner {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Runner{ctx: ctx, cancel: cancel}
+}
+
+// Every runs task once per interval, starting after the first interval elapses.
+// Errors are logged and do not stop the schedule.
+func (r *Runner) Every(name string, interval time.Duration, task func(ctx context.Context) error) {
+	if interval <= 0 {
+		log.Printf("Background job %s disabled (interval %v)", name, interval)
+		return
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := task(r.ctx); err != nil && r.ctx.Err() == nil {
+					log.Printf("Background job %s failed: %v", name, err)
+				}
+			case <-r.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Close stops every task and waits for running ones to return
+func (r *Runner) Close() {
+	r.cancel()
+	r.wg.Wait()
+}
@@ -1,10 +1,14 @@
 package jwt
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"time"
 
 	"github.com/1shoukr/swiftplay-backend/internal/config"
+	"github.com/1shoukr/swiftplay-backend/internal/revocation"
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -13,80 +17,270 @@ type Claims struct {
 	UserID    uint   `json:"user_id"`
 	Email     string `json:"email"`
 	Role      string `json:"role"`
-	TokenType string `json:"token_type"` 
+	TokenType string `json:"token_type"`
+	// IssuedAtMicros is iat in microseconds. iat only has whole seconds, which
+	// would make a token issued just after a revocation look older than it.
+	IssuedAtMicros int64 `json:"iat_us,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// JWTService handles JWT operations
+// TokenPair holds a freshly issued access and refresh token together with their expiry times
+type TokenPair struct {
+	AccessToken           string    `json:"access_token"`
+	AccessTokenID         string    `json:"-"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenID        string    `json:"-"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+	TokenType             string    `json:"token_type"`
+}
+
+// JWTService handles JWT operations. Access tokens are signed with HS256 and
+// JWT_SECRET unless a key directory is configured, in which case they are signed
+// with the active asymmetric key and carry its kid. Refresh tokens are only ever
+// verified by this service and always use HS256 with REFRESH_TOKEN_SECRET.
 type JWTService struct {
-	config *config.JWTConfig
+	config      *config.JWTConfig
+	revocations revocation.Store
+	keys        *KeySet
+
+	// requiredAudience, when set, restricts access-token validation to one audience
+	requiredAudience string
 }
 
 // NewJWTService creates a new JWT service instance
-func NewJWTService(config *config.JWTConfig) *JWTService {
-	return &JWTService{
-		config: config,
+func NewJWTService(config *config.JWTConfig, revocations revocation.Store) (*JWTService, error) {
+	service := &JWTService{
+		config:      config,
+		revocations: revocations,
 	}
+
+	if config.KeysDir != "" {
+		keys, err := NewKeySet(config.KeysDir, config.ActiveKeyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load signing keys: %w", err)
+		}
+		keys.Watch(config.KeysReloadInterval)
+		service.keys = keys
+	}
+
+	return service, nil
 }
 
 // GenerateAccessToken generates a new access token for a user
-func (j *JWTService) GenerateAccessToken(userID uint, email, role string) (string, error) {
-	claims := Claims{
-		UserID:    userID,
-		Email:     email,
-		Role:      role,
-		TokenType: "access",
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.config.Expiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    "swiftplay-backend",
-			Subject:   fmt.Sprintf("user:%d", userID),
-		},
+func (j *JWTService) GenerateAccessToken(userID uint, email, role, audience string) (string, error) {
+	claims, err := j.newClaims(userID, email, role, audience, "access", time.Now(), j.config.Expiry)
+	if err != nil {
+		return "", err
 	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(j.config.Secret))
+	return j.signAccessClaims(claims)
 }
 
 // GenerateRefreshToken generates a new refresh token for a user
-func (j *JWTService) GenerateRefreshToken(userID uint, email, role string) (string, error) {
-	claims := Claims{
-		UserID:    userID,
-		Email:     email,
-		Role:      role,
-		TokenType: "refresh",
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.config.RefreshTokenExpiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    "swiftplay-backend",
-			Subject:   fmt.Sprintf("user:%d", userID),
-		},
+func (j *JWTService) GenerateRefreshToken(userID uint, email, role, audience string) (string, error) {
+	claims, err := j.newClaims(userID, email, role, audience, "refresh", time.Now(), j.config.RefreshTokenExpiry)
+	if err != nil {
+		return "", err
 	}
+	return signClaims(claims, j.config.RefreshTokenSecret)
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(j.config.RefreshTokenSecret))
+// GenerateTokenPair generates both an access and a refresh token for a user
+// scoped to the given audience
+func (j *JWTService) GenerateTokenPair(userID uint, email, role, audience string) (*TokenPair, error) {
+	now := time.Now()
+
+	accessClaims, err := j.newClaims(userID, email, role, audience, "access", now, j.config.Expiry)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, err := j.signAccessClaims(accessClaims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	refreshClaims, err := j.newClaims(userID, email, role, audience, "refresh", now, j.config.RefreshTokenExpiry)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := signClaims(refreshClaims, j.config.RefreshTokenSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign refresh token: %w", err)
+	}
+
+	return &TokenPair{
+		AccessToken:           accessToken,
+		AccessTokenID:         accessClaims.ID,
+		AccessTokenExpiresAt:  accessClaims.ExpiresAt.Time,
+		RefreshToken:          refreshToken,
+		RefreshTokenID:        refreshClaims.ID,
+		RefreshTokenExpiresAt: refreshClaims.ExpiresAt.Time,
+		TokenType:             "Bearer",
+	}, nil
+}
+
+// GenerateMFAPendingToken generates a short-lived token proving the password step
+// of a login succeeded. It can only be exchanged for full tokens via a second factor.
+func (j *JWTService) GenerateMFAPendingToken(userID uint, email, role, audience string) (string, time.Time, error) {
+	claims, err := j.newClaims(userID, email, role, audience, "mfa_pending", time.Now(), j.config.MFAPendingExpiry)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	token, err := j.signAccessClaims(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, claims.ExpiresAt.Time, nil
 }
 
 // ValidateAccessToken validates and parses an access token
 func (j *JWTService) ValidateAccessToken(tokenString string) (*Claims, error) {
-	return j.validateToken(tokenString, j.config.Secret, "access")
+	return j.validateToken(tokenString, j.accessKeyFunc, "access")
 }
 
 // ValidateRefreshToken validates and parses a refresh token
 func (j *JWTService) ValidateRefreshToken(tokenString string) (*Claims, error) {
-	return j.validateToken(tokenString, j.config.RefreshTokenSecret, "refresh")
+	return j.validateToken(tokenString, hmacKeyFunc(j.config.RefreshTokenSecret), "refresh")
+}
+
+// ValidateMFAPendingToken validates and parses an mfa_pending token
+func (j *JWTService) ValidateMFAPendingToken(tokenString string) (*Claims, error) {
+	return j.validateToken(tokenString, j.accessKeyFunc, "mfa_pending")
+}
+
+// RevokeToken revokes a single access token until it expires
+func (j *JWTService) RevokeToken(ctx context.Context, claims *Claims) error {
+	if claims.ExpiresAt == nil {
+		return fmt.Errorf("token has no expiry")
+	}
+	return j.revocations.RevokeToken(ctx, claims.ID, claims.ExpiresAt.Time)
+}
+
+// RevokeAllForUser revokes every access token issued to the user up to now. The
+// cutoff has the microsecond precision of iat_us, so tokens issued after it in
+// the same second stay valid.
+func (j *JWTService) RevokeAllForUser(ctx context.Context, userID uint) error {
+	return j.revocations.RevokeUserTokens(ctx, userID, time.Now().Truncate(time.Microsecond))
+}
+
+// IsRevoked reports whether a validated access token has since been revoked
+func (j *JWTService) IsRevoked(ctx context.Context, claims *Claims) (bool, error) {
+	var issuedAt time.Time
+	switch {
+	case claims.IssuedAtMicros != 0:
+		issuedAt = time.UnixMicro(claims.IssuedAtMicros)
+	case claims.IssuedAt != nil:
+		issuedAt = claims.IssuedAt.Time
+	}
+	return j.revocations.IsRevoked(ctx, claims.ID, claims.UserID, issuedAt)
+}
+
+// ForAudience returns a view of the service whose access-token validation only
+// accepts tokens issued for the given audience. Route groups use it so that a
+// token minted for one client cannot be replayed against another.
+func (j *JWTService) ForAudience(audience string) *JWTService {
+	scoped := *j
+	scoped.requiredAudience = audience
+	return &scoped
 }
 
-// validateToken is a helper function to validate tokens
-func (j *JWTService) validateToken(tokenString, secret, expectedType string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+// LoginAudience resolves the audience a user login asks for. Logins default to
+// the mobile app; the admin console audience is only issued to elevated roles,
+// and the internal-services audience is never issued to users.
+func (j *JWTService) LoginAudience(requested string, elevated bool) (string, error) {
+	switch requested {
+	case "", j.config.MobileAudience:
+		return j.config.MobileAudience, nil
+	case j.config.AdminAudience:
+		if !elevated {
+			return "", fmt.Errorf("audience %q requires an elevated role", requested)
+		}
+		return j.config.AdminAudience, nil
+	default:
+		return "", fmt.Errorf("audience %q cannot be requested at login", requested)
+	}
+}
+
+// isAllowedAudience reports whether tokens may be issued for the audience
+func (j *JWTService) isAllowedAudience(audience string) bool {
+	for _, allowed := range j.config.Audiences() {
+		if audience == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// JWKS returns the public signing keys, empty when tokens are signed with HS256
+func (j *JWTService) JWKS() JWKS {
+	if j.keys == nil {
+		return JWKS{Keys: []JWK{}}
+	}
+	return j.keys.JWKS()
+}
+
+// ReloadKeys re-reads the signing key directory, picking up new and retired keys
+// and a changed active kid
+func (j *JWTService) ReloadKeys() error {
+	if j.keys == nil {
+		return nil
+	}
+	return j.keys.Reload()
+}
+
+// Close stops background key reloading
+func (j *JWTService) Close() {
+	if j.keys != nil {
+		j.keys.Close()
+	}
+}
+
+// signAccessClaims signs tokens that other services may need to verify
+func (j *JWTService) signAccessClaims(claims *Claims) (string, error) {
+	if j.keys != nil {
+		return j.keys.sign(claims)
+	}
+	return signClaims(claims, j.config.Secret)
+}
+
+// accessKeyFunc resolves the verification key for access-signed tokens. Tokens
+// without a kid were signed with JWT_SECRET. Once signing keys are configured
+// those are only accepted until JWT_HMAC_FALLBACK_UNTIL, so that tokens issued
+// before the switch can run out without JWT_SECRET staying usable for forgery.
+func (j *JWTService) accessKeyFunc(token *jwt.Token) (interface{}, error) {
+	if j.keys == nil {
+		return hmacKeyFunc(j.config.Secret)(token)
+	}
+	if _, hasKid := token.Header["kid"]; hasKid {
+		return j.keys.verificationKey(token)
+	}
+	if time.Now().Before(j.config.HMACFallbackUntil) {
+		return hmacKeyFunc(j.config.Secret)(token)
+	}
+	return nil, fmt.Errorf("token has no kid and HS256 access tokens are no longer accepted")
+}
+
+// hmacKeyFunc accepts only HMAC-signed tokens, verified with the given secret
+func hmacKeyFunc(secret string) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 		return []byte(secret), nil
-	})
+	}
+}
+
+// validateToken is a helper function to validate tokens. Issuer, expiry and
+// not-before are checked with the configured leeway for clock skew, and the
+// audience must be one this service issues for.
+func (j *JWTService) validateToken(tokenString string, keyFunc jwt.Keyfunc, expectedType string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, keyFunc,
+		jwt.WithIssuer(j.config.Issuer),
+		jwt.WithLeeway(j.config.Leeway),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
@@ -101,5 +295,57 @@ func (j *JWTService) validateToken(tokenString, secret, expectedType string) (*C
 		return nil, fmt.Errorf("invalid token type: expected %s, got %s", expectedType, claims.TokenType)
 	}
 
+	if len(claims.Audience) != 1 || !j.isAllowedAudience(claims.Audience[0]) {
+		return nil, fmt.Errorf("invalid token audience")
+	}
+
+	if j.requiredAudience != "" && claims.Audience[0] != j.requiredAudience {
+		return nil, fmt.Errorf("token audience %q is not accepted here", claims.Audience[0])
+	}
+
 	return claims, nil
 }
+
+// NewTokenID returns a random identifier suitable for the jti claim
+func NewTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// newClaims builds the claims shared by every token type, each with a unique jti
+func (j *JWTService) newClaims(userID uint, email, role, audience, tokenType string, issuedAt time.Time, expiry time.Duration) (*Claims, error) {
+	if !j.isAllowedAudience(audience) {
+		return nil, fmt.Errorf("audience %q is not allowed", audience)
+	}
+
+	tokenID, err := NewTokenID()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Claims{
+		UserID:         userID,
+		Email:          email,
+		Role:           role,
+		TokenType:      tokenType,
+		IssuedAtMicros: issuedAt.UnixMicro(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(issuedAt.Add(expiry)),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			NotBefore: jwt.NewNumericDate(issuedAt),
+			Issuer:    j.config.Issuer,
+			Subject:   fmt.Sprintf("user:%d", userID),
+			Audience:  jwt.ClaimStrings{audience},
+			ID:        tokenID,
+		},
+	}, nil
+}
+
+// signClaims signs the claims with HS256 using the given secret
+func signClaims(claims *Claims, secret string) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(secret))
+}
@@ -0,0 +1,103 @@
+package jwt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/config"
+	"github.com/1shoukr/swiftplay-backend/internal/revocation"
+)
+
+func testService(t *testing.T) *JWTService {
+	t.Helper()
+	service, err := NewJWTService(&config.JWTConfig{
+		Secret:             "test-access-secret-at-least-32-characters",
+		Expiry:             time.Hour,
+		RefreshTokenSecret: "test-refresh-secret-at-least-32-characters",
+		RefreshTokenExpiry: 24 * time.Hour,
+		Issuer:             "swiftplay-test",
+		MobileAudience:     "mobile",
+		AdminAudience:      "admin",
+		InternalAudience:   "internal",
+	}, revocation.NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewJWTService() error = %v", err)
+	}
+	return service
+}
+
+func issueAccessClaims(t *testing.T, service *JWTService, userID uint) *Claims {
+	t.Helper()
+	token, err := service.GenerateAccessToken(userID, "player@example.com", "user", "mobile")
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() error = %v", err)
+	}
+	claims, err := service.ValidateAccessToken(token)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken() error = %v", err)
+	}
+	return claims
+}
+
+func TestRevokeAllForUserWithinOneSecond(t *testing.T) {
+	ctx := context.Background()
+	service := testService(t)
+
+	// The pauses keep the three steps in distinct microseconds
+	before := issueAccessClaims(t, service, 1)
+	time.Sleep(time.Millisecond)
+	if err := service.RevokeAllForUser(ctx, 1); err != nil {
+		t.Fatalf("RevokeAllForUser() error = %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	after := issueAccessClaims(t, service, 1)
+	other := issueAccessClaims(t, service, 2)
+
+	if before.IssuedAt.Unix() != after.IssuedAt.Unix() {
+		t.Skip("the tokens were not issued within the same second")
+	}
+
+	tests := []struct {
+		name   string
+		claims *Claims
+		want   bool
+	}{
+		{"token issued before the revocation", before, true},
+		{"token issued after the revocation in the same second", after, false},
+		{"another user's token", other, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.IsRevoked(ctx, tt.claims)
+			if err != nil {
+				t.Fatalf("IsRevoked() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsRevoked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsRevokedWithoutMicrosecondClaim(t *testing.T) {
+	ctx := context.Background()
+	service := testService(t)
+
+	claims := issueAccessClaims(t, service, 1)
+	claims.IssuedAtMicros = 0
+	if err := service.RevokeAllForUser(ctx, 1); err != nil {
+		t.Fatalf("RevokeAllForUser() error = %v", err)
+	}
+
+	// Tokens issued before iat_us existed fall back to iat, which is truncated
+	// to the second and so precedes the cutoff
+	got, err := service.IsRevoked(ctx, claims)
+	if err != nil {
+		t.Fatalf("IsRevoked() error = %v", err)
+	}
+	if !got {
+		t.Errorf("IsRevoked() = false, want true")
+	}
+}
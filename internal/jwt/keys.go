@@ -0,0 +1,254 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// activeKeyFile, when present in the keys directory, names the kid used for
+// signing and takes precedence over JWT_ACTIVE_KID so rotation needs no restart
+const activeKeyFile = "active_kid"
+
+// signingKey is one entry of the key set
+type signingKey struct {
+	id        string
+	method    jwt.SigningMethod
+	private   crypto.Signer
+	publicKey crypto.PublicKey
+}
+
+// KeySet holds the asymmetric keys access tokens are signed and verified with.
+// Every key found in the directory is accepted for verification; only the active
+// one is used to sign, so retired keys keep verifying tokens until they expire.
+type KeySet struct {
+	dir       string
+	defaultID string
+
+	mu       sync.RWMutex
+	keys     map[string]*signingKey
+	activeID string
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// JWK is a public key in JSON Web Key format
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set document
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewKeySet loads every <kid>.pem private key from dir
+func NewKeySet(dir, defaultActiveID string) (*KeySet, error) {
+	ks := &KeySet{
+		dir:       dir,
+		defaultID: defaultActiveID,
+		stop:      make(chan struct{}),
+	}
+
+	if err := ks.Reload(); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// Reload re-reads the keys directory. On failure the previously loaded keys stay in use.
+func (ks *KeySet) Reload() error {
+	paths, err := filepath.Glob(filepath.Join(ks.dir, "*.pem"))
+	if err != nil {
+		return fmt.Errorf("failed to list signing keys: %w", err)
+	}
+
+	keys := make(map[string]*signingKey, len(paths))
+	for _, path := range paths {
+		key, err := loadSigningKey(path)
+		if err != nil {
+			return err
+		}
+		keys[key.id] = key
+	}
+
+	activeID := ks.defaultID
+	if contents, err := os.ReadFile(filepath.Join(ks.dir, activeKeyFile)); err == nil {
+		activeID = strings.TrimSpace(string(contents))
+	}
+
+	if activeID == "" {
+		return fmt.Errorf("no active signing key configured: set JWT_ACTIVE_KID or write %s", activeKeyFile)
+	}
+	if _, ok := keys[activeID]; !ok {
+		return fmt.Errorf("active signing key %q not found in %s", activeID, ks.dir)
+	}
+
+	ks.mu.Lock()
+	previous := ks.activeID
+	ks.keys = keys
+	ks.activeID = activeID
+	ks.mu.Unlock()
+
+	if previous != activeID {
+		log.Printf("JWT signing key set loaded - Active kid: %s, Keys: %d", activeID, len(keys))
+	}
+	return nil
+}
+
+// Watch reloads the key set on the given interval until Close is called
+func (ks *KeySet) Watch(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := ks.Reload(); err != nil {
+					log.Printf("Failed to reload JWT signing keys: %v", err)
+				}
+			case <-ks.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the background reload started by Watch
+func (ks *KeySet) Close() {
+	ks.once.Do(func() { close(ks.stop) })
+}
+
+// sign signs the claims with the active key, recording its kid in the header
+func (ks *KeySet) sign(claims *Claims) (string, error) {
+	ks.mu.RLock()
+	key := ks.keys[ks.activeID]
+	ks.mu.RUnlock()
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.id
+	return token.SignedString(key.private)
+}
+
+// verificationKey returns the public key for the token's kid, rejecting tokens
+// whose algorithm does not match the key
+func (ks *KeySet) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	ks.mu.RLock()
+	key, ok := ks.keys[kid]
+	ks.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key: %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.publicKey, nil
+}
+
+// JWKS returns the public half of every key in the set
+func (ks *KeySet) JWKS() JWKS {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	ids := make([]string, 0, len(ks.keys))
+	for id := range ks.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	set := JWKS{Keys: make([]JWK, 0, len(ids))}
+	for _, id := range ids {
+		key := ks.keys[id]
+		jwk := JWK{Use: "sig", Algorithm: key.method.Alg(), KeyID: key.id}
+
+		switch pub := key.publicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// loadSigningKey parses a PKCS#8 (RSA or Ed25519) or PKCS#1 (RSA) private key;
+// the kid is the file name without its extension
+func loadSigningKey(path string) (*signingKey, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key %s: %w", path, err)
+	}
+
+	block, _ := pem.Decode(contents)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %s is not PEM encoded", path)
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("signing key %s has unsupported PEM type %q", path, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key %s: %w", path, err)
+	}
+
+	key := &signingKey{id: strings.TrimSuffix(filepath.Base(path), ".pem")}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("RSA signing key %s must be at least 2048 bits", path)
+		}
+		key.method = jwt.SigningMethodRS256
+		key.private = k
+		key.publicKey = &k.PublicKey
+	case ed25519.PrivateKey:
+		key.method = jwt.SigningMethodEdDSA
+		key.private = k
+		key.publicKey = k.Public()
+	default:
+		return nil, fmt.Errorf("signing key %s must be an RSA or Ed25519 key", path)
+	}
+
+	return key, nil
+}
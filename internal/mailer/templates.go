@@ -0,0 +1,38 @@
+package mailer
+
+import (
+	"fmt"
+	"time"
+)
+
+// VerificationEmail builds the message asking a new user to confirm their address
+func VerificationEmail(to, username, link string, expiry time.Duration) Message {
+	return Message{
+		To:      to,
+		Subject: "Verify your SwiftPlay email address",
+		Body: fmt.Sprintf(`Hi %s,
+
+Welcome to SwiftPlay! Please confirm your email address by opening the link below:
+
+%s
+
+This link expires in %s. If you did not create a SwiftPlay account, you can ignore this email.
+`, username, link, expiry),
+	}
+}
+
+// PasswordResetEmail builds the message carrying a password reset link
+func PasswordResetEmail(to, username, link string, expiry time.Duration) Message {
+	return Message{
+		To:      to,
+		Subject: "Reset your SwiftPlay password",
+		Body: fmt.Sprintf(`Hi %s,
+
+We received a request to reset your SwiftPlay password. Open the link below to choose a new one:
+
+%s
+
+This link expires in %s and can only be used once. If you did not ask to reset your password, you can ignore this email.
+`, username, link, expiry),
+	}
+}
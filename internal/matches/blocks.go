@@ -0,0 +1,61 @@
+package matches
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Block stops the two players from finding, liking or messaging each other. Any
+// match between them is closed for good, whoever blocked whom; it stays closed
+// if the block is lifted later. The returned match is nil when the pair has none.
+func (s *Service) Block(ctx context.Context, blockerID, blockedID uint) (*models.Match, error) {
+	var match *models.Match
+	var previous string
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		block := models.Block{BlockerID: blockerID, BlockedID: blockedID}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&block).Error; err != nil {
+			return err
+		}
+
+		userID1, userID2 := models.MatchPair(blockerID, blockedID)
+		var err error
+		match, err = lockPair(tx, userID1, userID2)
+		if errors.Is(err, ErrMatchNotFound) {
+			match = nil
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		previous = match.Status
+		if !CanTransition(match.Status, models.MatchStatusBlocked) {
+			return nil
+		}
+		return transition(tx, match, models.MatchStatusBlocked, &blockerID, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if match != nil {
+		s.notify(match, previous, blockerID)
+	}
+	return match, nil
+}
+
+// Unblock lifts a block the blocker placed. It reports whether there was one.
+func (s *Service) Unblock(ctx context.Context, blockerID, blockedID uint) (bool, error) {
+	result := s.db.WithContext(ctx).
+		Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).
+		Delete(&models.Block{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
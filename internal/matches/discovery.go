@@ -0,0 +1,28 @@
+package matches
+
+import (
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// Discoverable selects profiles joined with their users that the viewer may be
+// shown when looking for players. The viewer, deleted, suspended and banned
+// accounts, players blocked in either direction, anyone the viewer already has
+// a match with and players the viewer passed on after passedSince are left out.
+func Discoverable(db *gorm.DB, viewerID uint, passedSince time.Time) *gorm.DB {
+	return db.Table("profiles").
+		Joins("JOIN users ON users.user_id = profiles.user_id").
+		Where("users.deleted_at IS NULL AND users.soft_delete = ?", false).
+		Where("users.banned_at IS NULL AND (users.suspended_until IS NULL OR users.suspended_until <= ?)", time.Now()).
+		Where("users.user_id <> ?", viewerID).
+		Where(`NOT EXISTS (SELECT 1 FROM blocks WHERE
+			(blocks.blocker_id = ? AND blocks.blocked_id = users.user_id) OR
+			(blocks.blocker_id = users.user_id AND blocks.blocked_id = ?))`, viewerID, viewerID).
+		Where(`NOT EXISTS (SELECT 1 FROM matches WHERE
+			(matches.user_id_1 = ? AND matches.user_id_2 = users.user_id) OR
+			(matches.user_id_1 = users.user_id AND matches.user_id_2 = ?))`, viewerID, viewerID).
+		Where(`NOT EXISTS (SELECT 1 FROM swipes WHERE swipes.swiper_id = ? AND swipes.target_id = users.user_id
+			AND swipes.action = ? AND swipes.updated_at > ?)`, viewerID, models.SwipePass, passedSince)
+}
@@ -0,0 +1,328 @@
+package matches
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"github.com/1shoukr/swiftplay-backend/internal/realtime"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrMatchNotFound     = errors.New("match not found")
+	ErrInvalidTransition = errors.New("match cannot move to that status")
+)
+
+// transitions lists, for every status, the statuses a match may move to next.
+// A new like reopens declined and expired matches; unmatching and blocking are
+// permanent.
+var transitions = map[string][]string{
+	models.MatchStatusPending:   {models.MatchStatusAccepted, models.MatchStatusDeclined, models.MatchStatusUnmatched, models.MatchStatusExpired, models.MatchStatusBlocked},
+	models.MatchStatusAccepted:  {models.MatchStatusUnmatched, models.MatchStatusBlocked},
+	models.MatchStatusDeclined:  {models.MatchStatusPending, models.MatchStatusBlocked},
+	models.MatchStatusExpired:   {models.MatchStatusPending, models.MatchStatusBlocked},
+	models.MatchStatusUnmatched: {},
+	models.MatchStatusBlocked:   {},
+}
+
+// Statuses lists every match status
+func Statuses() []string {
+	return []string{
+		models.MatchStatusPending,
+		models.MatchStatusAccepted,
+		models.MatchStatusDeclined,
+		models.MatchStatusUnmatched,
+		models.MatchStatusExpired,
+		models.MatchStatusBlocked,
+	}
+}
+
+// IsStatus reports whether status is a defined match status
+func IsStatus(status string) bool {
+	_, ok := transitions[status]
+	return ok
+}
+
+// CanTransition reports whether a match in status from may move to status to
+func CanTransition(from, to string) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Service owns every change to match status and tells both players about it
+type Service struct {
+	db        *gorm.DB
+	publisher realtime.Publisher
+}
+
+func NewService(db *gorm.DB, publisher realtime.Publisher) *Service {
+	return &Service{db: db, publisher: publisher}
+}
+
+// Swipe records the swiper's decision about the target and applies its effect
+// on their match. A like opens a pending match, or accepts it when the target
+// already liked the swiper; a pass declines a pending match the target opened.
+// The returned match is nil when the pair has none.
+func (s *Service) Swipe(ctx context.Context, swiperID, targetID uint, action string) (*models.Match, error) {
+	var match *models.Match
+	var previous string
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		swipe := models.Swipe{SwiperID: swiperID, TargetID: targetID, Action: action}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "swiper_id"}, {Name: "target_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"action", "updated_at"}),
+		}).Create(&swipe).Error; err != nil {
+			return err
+		}
+
+		var err error
+		if action == models.SwipePass {
+			match, previous, err = pass(tx, swiperID, targetID)
+		} else {
+			match, previous, err = like(tx, swiperID, targetID)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if match != nil {
+		s.notify(match, previous, swiperID)
+	}
+	return match, nil
+}
+
+// like makes sure the pair has a match and accepts it when the target has liked
+// the swiper back. The pair's row is locked before the reverse like is checked,
+// so two players liking each other at the same time serialize on it: whichever
+// commits second sees the other's like and accepts the match. It also returns
+// the status the match had before, which is empty for a new match.
+func like(tx *gorm.DB, swiperID, targetID uint) (*models.Match, string, error) {
+	userID1, userID2 := models.MatchPair(swiperID, targetID)
+	now := time.Now()
+
+	created := models.Match{
+		UserID1:     userID1,
+		UserID2:     userID2,
+		InitiatorID: swiperID,
+		Status:      models.MatchStatusPending,
+		PendingAt:   &now,
+	}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&created)
+	if result.Error != nil {
+		return nil, "", result.Error
+	}
+
+	match, err := lockPair(tx, userID1, userID2)
+	if err != nil {
+		return nil, "", err
+	}
+
+	previous := match.Status
+	if result.RowsAffected > 0 {
+		previous = ""
+		if err := recordTransition(tx, match.MatchID, "", models.MatchStatusPending, &swiperID, now); err != nil {
+			return nil, "", err
+		}
+	} else if CanTransition(match.Status, models.MatchStatusPending) {
+		if err := transition(tx, match, models.MatchStatusPending, &swiperID, now); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if match.Status != models.MatchStatusPending {
+		return match, previous, nil
+	}
+
+	var likedBack int64
+	if err := tx.Model(&models.Swipe{}).
+		Where("swiper_id = ? AND target_id = ? AND action IN ?", targetID, swiperID, []string{models.SwipeLike, models.SwipeSuperLike}).
+		Count(&likedBack).Error; err != nil {
+		return nil, "", err
+	}
+
+	if likedBack > 0 {
+		if err := transition(tx, match, models.MatchStatusAccepted, &swiperID, now); err != nil {
+			return nil, "", err
+		}
+	}
+	return match, previous, nil
+}
+
+// pass declines a pending match opened by the target. Other matches are left
+// alone. It also returns the status the match had before.
+func pass(tx *gorm.DB, swiperID, targetID uint) (*models.Match, string, error) {
+	userID1, userID2 := models.MatchPair(swiperID, targetID)
+
+	match, err := lockPair(tx, userID1, userID2)
+	if errors.Is(err, ErrMatchNotFound) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	previous := match.Status
+	if match.Status == models.MatchStatusPending && match.InitiatorID == targetID {
+		if err := transition(tx, match, models.MatchStatusDeclined, &swiperID, time.Now()); err != nil {
+			return nil, "", err
+		}
+	}
+	return match, previous, nil
+}
+
+// Unmatch ends a pending or accepted match on behalf of one of its players
+func (s *Service) Unmatch(ctx context.Context, matchID, actorID uint) (*models.Match, error) {
+	var match *models.Match
+	var previous string
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var matches []models.Match
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("match_id = ?", matchID).Limit(1).Find(&matches).Error; err != nil {
+			return err
+		}
+		if len(matches) == 0 || !matches[0].HasParticipant(actorID) {
+			return ErrMatchNotFound
+		}
+
+		match = &matches[0]
+		previous = match.Status
+		return transition(tx, match, models.MatchStatusUnmatched, &actorID, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(match, previous, actorID)
+	return match, nil
+}
+
+// ExpirePending expires every match that has been pending since before cutoff
+func (s *Service) ExpirePending(ctx context.Context, cutoff time.Time) (int, error) {
+	var stale []models.Match
+	expired := 0
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND pending_at < ?", models.MatchStatusPending, cutoff).
+			Limit(500).Find(&stale).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for i := range stale {
+			if err := transition(tx, &stale[i], models.MatchStatusExpired, nil, now); err != nil {
+				return err
+			}
+			expired++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range stale {
+		s.notify(&stale[i], models.MatchStatusPending, 0)
+	}
+	if expired > 0 {
+		log.Printf("Expired %d pending matches", expired)
+	}
+	return expired, nil
+}
+
+// notify tells both players that their match was created or changed status.
+// actorID is the player who caused it, or zero for background jobs.
+func (s *Service) notify(match *models.Match, previous string, actorID uint) {
+	if match.Status == previous {
+		return
+	}
+
+	eventType := realtime.EventMatchUpdated
+	if previous == "" {
+		eventType = realtime.EventMatchCreated
+	}
+	s.publisher.Publish([]uint{match.UserID1, match.UserID2}, realtime.Event{
+		Type:    eventType,
+		MatchID: match.MatchID,
+		UserID:  actorID,
+		Data: map[string]interface{}{
+			"match":           match,
+			"previous_status": previous,
+		},
+	})
+}
+
+// lockPair loads the pair's match and locks it for the rest of the transaction
+func lockPair(tx *gorm.DB, userID1, userID2 uint) (*models.Match, error) {
+	var matches []models.Match
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id_1 = ? AND user_id_2 = ?", userID1, userID2).
+		Limit(1).Find(&matches).Error; err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, ErrMatchNotFound
+	}
+	return &matches[0], nil
+}
+
+// transition moves a locked match to a new status, stamping the time of the
+// change and recording who made it
+func transition(tx *gorm.DB, match *models.Match, to string, actorID *uint, at time.Time) error {
+	if !CanTransition(match.Status, to) {
+		return ErrInvalidTransition
+	}
+
+	updates := map[string]interface{}{"status": to}
+	switch to {
+	case models.MatchStatusPending:
+		match.PendingAt = &at
+		updates["pending_at"] = at
+		if actorID != nil {
+			match.InitiatorID = *actorID
+			updates["initiator_id"] = *actorID
+		}
+	case models.MatchStatusAccepted:
+		match.AcceptedAt = &at
+		updates["accepted_at"] = at
+	case models.MatchStatusDeclined:
+		match.DeclinedAt = &at
+		updates["declined_at"] = at
+	case models.MatchStatusUnmatched:
+		match.UnmatchedAt = &at
+		updates["unmatched_at"] = at
+	case models.MatchStatusExpired:
+		match.ExpiredAt = &at
+		updates["expired_at"] = at
+	case models.MatchStatusBlocked:
+		match.BlockedAt = &at
+		updates["blocked_at"] = at
+	}
+
+	if err := tx.Model(&models.Match{}).Where("match_id = ?", match.MatchID).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	from := match.Status
+	match.Status = to
+	return recordTransition(tx, match.MatchID, from, to, actorID, at)
+}
+
+func recordTransition(tx *gorm.DB, matchID uint, from, to string, actorID *uint, at time.Time) error {
+	return tx.Create(&models.MatchTransition{
+		MatchID:    matchID,
+		FromStatus: from,
+		ToStatus:   to,
+		ActorID:    actorID,
+		CreatedAt:  at,
+	}).Error
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/1shoukr/swiftplay-backend/internal/audit"
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditRequests records every authenticated request to the group in the audit
+// log once it has been handled, including those refused for missing
+// permissions. Requests without a valid token never reach a user and are not
+// recorded.
+func AuditRequests(logger *audit.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		userID, _, role, exists := GetUserFromContext(c)
+		if !exists {
+			return
+		}
+
+		status := c.Writer.Status()
+		outcome := models.AuditOutcomeSuccess
+		switch {
+		case status == http.StatusUnauthorized || status == http.StatusForbidden:
+			outcome = models.AuditOutcomeDenied
+		case status >= http.StatusBadRequest:
+			outcome = models.AuditOutcomeFailure
+		}
+
+		logger.RecordRequest(c, audit.Entry{
+			Event:    audit.EventPrivilegedRequest,
+			ActorID:  userID,
+			TargetID: audit.Target(c),
+			Outcome:  outcome,
+			Details: map[string]interface{}{
+				"method": c.Request.Method,
+				"route":  c.FullPath(),
+				"path":   c.Request.URL.Path,
+				"status": status,
+				"role":   role,
+			},
+		})
+	}
+}
@@ -5,32 +5,18 @@ import (
 	"strings"
 
 	"github.com/1shoukr/swiftplay-backend/internal/jwt"
+	"github.com/1shoukr/swiftplay-backend/internal/rbac"
 	"github.com/gin-gonic/gin"
 )
 
 // AuthMiddleware validates JWT tokens and injects user data into context
 func AuthMiddleware(jwtService *jwt.JWTService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Authorization header is required",
-			})
-			c.Abort()
-			return
-		}
-
-		bearerToken := strings.Split(authHeader, " ")
-		if len(bearerToken) != 2 || bearerToken[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid authorization header format. Expected: Bearer <token>",
-			})
-			c.Abort()
+		token, ok := bearerToken(c)
+		if !ok {
 			return
 		}
 
-		token := bearerToken[1]
-
 		claims, err := jwtService.ValidateAccessToken(token)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
@@ -41,145 +27,82 @@ func AuthMiddleware(jwtService *jwt.JWTService) gin.HandlerFunc {
 			return
 		}
 
-		c.Set("user_id", claims.UserID)
-		c.Set("email", claims.Email)
-		c.Set("role", claims.Role)
-		c.Set("user_claims", claims)
-
-		c.Next()
-	}
-}
-
-// RequireAuth is a basic auth middleware that just validates tokens
-func RequireAuth(jwtService *jwt.JWTService) gin.HandlerFunc {
-	return AuthMiddleware(jwtService)
-}
-
-// RequireUser ensures the user has at least "user" level access
-func RequireUser(jwtService *jwt.JWTService) gin.HandlerFunc {
-	return gin.HandlerFunc(func(c *gin.Context) {
-		// First validate the token
-		AuthMiddleware(jwtService)(c)
-		if c.IsAborted() {
+		if !checkNotRevoked(c, jwtService, claims) {
 			return
 		}
 
-		// Check role (user is the minimum level)
-		role, exists := c.Get("role")
-		if !exists {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "User role not found in context",
-			})
-			c.Abort()
-			return
-		}
-
-		roleStr, ok := role.(string)
-		if !ok {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Invalid role format",
-			})
-			c.Abort()
-			return
-		}
+		setClaims(c, claims)
 
-		validRoles := []string{"user", "admin", "super_admin", "engineer"}
-		if !contains(validRoles, roleStr) {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error": "Insufficient permissions. User access required.",
-			})
-			c.Abort()
+		if !applyUserState(c, claims.UserID) {
 			return
 		}
 
 		c.Next()
-	})
+	}
 }
 
-// RequireAdmin ensures the user has admin level access or higher
-func RequireAdmin(jwtService *jwt.JWTService) gin.HandlerFunc {
-	return gin.HandlerFunc(func(c *gin.Context) {
-		// First validate the token
-		AuthMiddleware(jwtService)(c)
-		if c.IsAborted() {
-			return
-		}
-
-		// Check role
-		role, exists := c.Get("role")
-		if !exists {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "User role not found in context",
-			})
-			c.Abort()
-			return
-		}
-
-		roleStr, ok := role.(string)
-		if !ok {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Invalid role format",
-			})
-			c.Abort()
-			return
-		}
+// bearerToken extracts the token from the Authorization header, aborting the
+// request with 401 when it is missing or malformed
+func bearerToken(c *gin.Context) (string, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authorization header is required",
+		})
+		c.Abort()
+		return "", false
+	}
 
-		validRoles := []string{"admin", "super_admin", "engineer"}
-		if !contains(validRoles, roleStr) {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error": "Insufficient permissions. Admin access required.",
-			})
-			c.Abort()
-			return
-		}
+	bearerToken := strings.Split(authHeader, " ")
+	if len(bearerToken) != 2 || bearerToken[0] != "Bearer" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Invalid authorization header format. Expected: Bearer <token>",
+		})
+		c.Abort()
+		return "", false
+	}
 
-		c.Next()
-	})
+	return bearerToken[1], true
 }
 
-// RequireSuperAdmin ensures the user has super_admin level access or engineer
-func RequireSuperAdmin(jwtService *jwt.JWTService) gin.HandlerFunc {
-	return gin.HandlerFunc(func(c *gin.Context) {
-		// First validate the token
-		AuthMiddleware(jwtService)(c)
-		if c.IsAborted() {
-			return
-		}
+// checkNotRevoked aborts the request when the token has been revoked
+func checkNotRevoked(c *gin.Context, jwtService *jwt.JWTService, claims *jwt.Claims) bool {
+	revoked, err := jwtService.IsRevoked(c.Request.Context(), claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to check token revocation",
+		})
+		c.Abort()
+		return false
+	}
 
-		// Check role
-		role, exists := c.Get("role")
-		if !exists {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "User role not found in context",
-			})
-			c.Abort()
-			return
-		}
+	if revoked {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Token has been revoked",
+		})
+		c.Abort()
+		return false
+	}
 
-		roleStr, ok := role.(string)
-		if !ok {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Invalid role format",
-			})
-			c.Abort()
-			return
-		}
+	return true
+}
 
-		validRoles := []string{"super_admin", "engineer"}
-		if !contains(validRoles, roleStr) {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error": "Insufficient permissions. Super admin access required.",
-			})
-			c.Abort()
-			return
-		}
+// setClaims injects the token's user data into the context
+func setClaims(c *gin.Context, claims *jwt.Claims) {
+	c.Set("user_id", claims.UserID)
+	c.Set("email", claims.Email)
+	c.Set("role", claims.Role)
+	c.Set("user_claims", claims)
+}
 
-		c.Next()
-	})
+// RequireAuth is a basic auth middleware that just validates tokens
+func RequireAuth(jwtService *jwt.JWTService) gin.HandlerFunc {
+	return AuthMiddleware(jwtService)
 }
 
-// RequireEngineer ensures the user has engineer level access (highest level)
-func RequireEngineer(jwtService *jwt.JWTService) gin.HandlerFunc {
+// RequirePermission validates the token and ensures the caller's role holds every
+// one of the given permissions under the RBAC policy
+func RequirePermission(jwtService *jwt.JWTService, policy *rbac.Policy, permissions ...string) gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
 		// First validate the token
 		AuthMiddleware(jwtService)(c)
@@ -206,9 +129,10 @@ func RequireEngineer(jwtService *jwt.JWTService) gin.HandlerFunc {
 			return
 		}
 
-		if roleStr != "engineer" {
+		if !policy.HasRole(roleStr) || !policy.HasAll(roleStr, permissions...) {
 			c.JSON(http.StatusForbidden, gin.H{
-				"error": "Insufficient permissions. Engineer access required.",
+				"error":    "Insufficient permissions",
+				"required": permissions,
 			})
 			c.Abort()
 			return
@@ -218,16 +142,6 @@ func RequireEngineer(jwtService *jwt.JWTService) gin.HandlerFunc {
 	})
 }
 
-// Helper function to check if a slice contains a string
-func contains(slice []string, item string) bool {
-	for _, s := range slice {
-		if s == item {
-			return true
-		}
-	}
-	return false
-}
-
 // GetUserFromContext extracts user information from the Gin context
 func GetUserFromContext(c *gin.Context) (userID uint, email string, role string, exists bool) {
 	userIDVal, userIDExists := c.Get("user_id")
@@ -248,3 +162,14 @@ func GetUserFromContext(c *gin.Context) (userID uint, email string, role string,
 
 	return userID, email, role, true
 }
+
+// GetClaimsFromContext returns the validated token claims stored by AuthMiddleware
+func GetClaimsFromContext(c *gin.Context) (*jwt.Claims, bool) {
+	claimsVal, exists := c.Get("user_claims")
+	if !exists {
+		return nil, false
+	}
+
+	claims, ok := claimsVal.(*jwt.Claims)
+	return claims, ok
+}
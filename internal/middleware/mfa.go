@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/1shoukr/swiftplay-backend/internal/jwt"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireMFASetup authenticates MFA enrollment requests. It accepts a regular
+// access token, or an mfa_pending token so that users who must enroll before
+// they can finish logging in are able to do so.
+func RequireMFASetup(jwtService *jwt.JWTService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c)
+		if !ok {
+			return
+		}
+
+		claims, err := jwtService.ValidateAccessToken(token)
+		if err != nil {
+			claims, err = jwtService.ValidateMFAPendingToken(token)
+		}
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "Invalid or expired token",
+				"details": err.Error(),
+			})
+			c.Abort()
+			return
+		}
+
+		if !checkNotRevoked(c, jwtService, claims) {
+			return
+		}
+
+		setClaims(c, claims)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/1shoukr/swiftplay-backend/internal/userstate"
+	"github.com/gin-gonic/gin"
+)
+
+const userStateLoaderKey = "user_state_loader"
+
+// UserStateLoader returns the current account state for a user
+type UserStateLoader interface {
+	Load(ctx context.Context, userID uint) (*userstate.State, error)
+}
+
+// WithUserStates turns on live account lookups for every request. When installed,
+// AuthMiddleware replaces the role claim with the user's current auth level and
+// rejects deleted accounts, instead of trusting the token until it expires.
+func WithUserStates(loader UserStateLoader) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(userStateLoaderKey, loader)
+		c.Next()
+	}
+}
+
+// applyUserState overrides the token's role with the live one when live lookups
+// are enabled, aborting the request for accounts that are no longer active
+func applyUserState(c *gin.Context, userID uint) bool {
+	loaderVal, exists := c.Get(userStateLoaderKey)
+	if !exists {
+		return true
+	}
+
+	loader, ok := loaderVal.(UserStateLoader)
+	if !ok {
+		return true
+	}
+
+	state, err := loader.Load(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load account state",
+		})
+		c.Abort()
+		return false
+	}
+
+	if !state.Active {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Account is no longer active",
+		})
+		c.Abort()
+		return false
+	}
+
+	c.Set("role", state.AuthLevel)
+	return true
+}
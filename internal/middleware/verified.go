@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/1shoukr/swiftplay-backend/internal/database"
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireVerifiedEmail rejects callers whose email address has not been verified.
+// It must run after one of the token-validating middlewares.
+func RequireVerifiedEmail(db *database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _, _, exists := GetUserFromContext(c)
+		if !exists {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "User data not found in context",
+			})
+			c.Abort()
+			return
+		}
+
+		var users []models.User
+		if err := db.GetDB().Select("user_id", "email_verified_at").
+			Where("user_id = ?", userID).Limit(1).Find(&users).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to load user",
+			})
+			c.Abort()
+			return
+		}
+
+		if len(users) == 0 || users[0].EmailVerifiedAt == nil {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Email verification required",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
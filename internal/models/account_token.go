@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// Purposes an AccountToken can be issued for
+const (
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposePasswordReset     = "password_reset"
+)
+
+// AccountToken is a single-use token emailed to a user. Only the SHA-256 hash of
+// the token is stored, so a database leak does not expose usable tokens.
+type AccountToken struct {
+	TokenID   uint       `json:"token_id" gorm:"primaryKey;autoIncrement;column:token_id"`
+	UserID    uint       `json:"user_id" gorm:"not null;index;column:user_id"`
+	Purpose   string     `json:"purpose" gorm:"not null;size:32;index"`
+	TokenHash string     `json:"-" gorm:"uniqueIndex;not null;size:64;column:token_hash"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null;column:expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty" gorm:"column:used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+// Admin actions taken on user accounts
+const (
+	AdminActionSuspend    = "suspend"
+	AdminActionBan        = "ban"
+	AdminActionReinstate  = "reinstate"
+	AdminActionRestore    = "restore"
+	AdminActionRoleChange = "role_change"
+)
+
+// AdminAction records a moderation or account change made by an admin on a
+// user's account
+type AdminAction struct {
+	ActionID uint   `json:"action_id" gorm:"primaryKey;autoIncrement;column:action_id"`
+	ActorID  uint   `json:"actor_id" gorm:"not null;index;column:actor_id"`
+	TargetID uint   `json:"target_id" gorm:"not null;index;column:target_id"`
+	Action   string `json:"action" gorm:"not null;size:20"`
+	Reason   string `json:"reason,omitempty" gorm:"type:text"`
+	// Details holds what changed, such as the previous and new role
+	Details   map[string]interface{} `json:"details,omitempty" gorm:"type:jsonb;serializer:json"`
+	CreatedAt time.Time              `json:"created_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// Audit record outcomes
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+	AuditOutcomeDenied  = "denied"
+)
+
+// AuditRecord is one entry of the append-only audit log. Each record stores the
+// hash of the record before it, so changing or removing a record breaks the
+// chain from that point on.
+type AuditRecord struct {
+	RecordID  uint   `json:"record_id" gorm:"primaryKey;autoIncrement;column:record_id"`
+	Event     string `json:"event" gorm:"not null;size:50;index"`
+	ActorID   *uint  `json:"actor_id" gorm:"index;column:actor_id"`
+	TargetID  *uint  `json:"target_id" gorm:"index;column:target_id"`
+	IP        string `json:"ip" gorm:"size:45;column:ip"`
+	UserAgent string `json:"user_agent" gorm:"type:text"`
+	Outcome   string `json:"outcome" gorm:"not null;size:10"`
+	// Details is kept as the exact JSON text that was hashed, since jsonb would
+	// normalize it
+	Details   string    `json:"-" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+	PrevHash  string    `json:"prev_hash" gorm:"size:64"`
+	Hash      string    `json:"hash" gorm:"not null;size:64;uniqueIndex"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import (
+	"time"
+)
+
+// Block hides two players from each other. It is one-directional: BlockerID chose
+// to block BlockedID.
+type Block struct {
+	BlockerID uint      `json:"blocker_id" gorm:"primaryKey;autoIncrement:false;column:blocker_id"`
+	BlockedID uint      `json:"blocked_id" gorm:"primaryKey;autoIncrement:false;index;column:blocked_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
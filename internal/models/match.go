@@ -4,10 +4,63 @@ import (
 	"time"
 )
 
+// Match statuses. Allowed transitions are enforced by the matches package.
+const (
+	MatchStatusPending   = "pending"
+	MatchStatusAccepted  = "accepted"
+	MatchStatusDeclined  = "declined"
+	MatchStatusUnmatched = "unmatched"
+	MatchStatusExpired   = "expired"
+	MatchStatusBlocked   = "blocked"
+)
+
+// Match links two players. UserID1 is always the lower user id so that each
+// pair has exactly one row; InitiatorID is the player whose like opened the
+// current pending period.
 type Match struct {
-	MatchID   uint      `json:"match_id" gorm:"primaryKey;autoIncrement;column:match_id"`
-	UserID1   uint      `json:"user_id_1" gorm:"not null;index;column:user_id_1"`
-	UserID2   uint      `json:"user_id_2" gorm:"not null;index;column:user_id_2"`
-	Status    string    `json:"status" gorm:"not null;size:20;default:'pending'"`
-	CreatedAt time.Time `json:"created_at"`
+	MatchID     uint       `json:"match_id" gorm:"primaryKey;autoIncrement;column:match_id"`
+	UserID1     uint       `json:"user_id_1" gorm:"not null;index;uniqueIndex:idx_matches_pair;column:user_id_1"`
+	UserID2     uint       `json:"user_id_2" gorm:"not null;index;uniqueIndex:idx_matches_pair;column:user_id_2"`
+	InitiatorID uint       `json:"initiator_id" gorm:"not null;default:0;column:initiator_id"`
+	Status      string     `json:"status" gorm:"not null;size:20;default:'pending';index:idx_matches_status_pending,priority:1"`
+	PendingAt   *time.Time `json:"pending_at,omitempty" gorm:"index:idx_matches_status_pending,priority:2;column:pending_at"`
+	AcceptedAt  *time.Time `json:"accepted_at,omitempty" gorm:"column:accepted_at"`
+	DeclinedAt  *time.Time `json:"declined_at,omitempty" gorm:"column:declined_at"`
+	UnmatchedAt *time.Time `json:"unmatched_at,omitempty" gorm:"column:unmatched_at"`
+	ExpiredAt   *time.Time `json:"expired_at,omitempty" gorm:"column:expired_at"`
+	BlockedAt   *time.Time `json:"blocked_at,omitempty" gorm:"column:blocked_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// MatchTransition records one status change of a match. ActorID is nil when the
+// system made the change, such as expiring a pending match.
+type MatchTransition struct {
+	TransitionID uint      `json:"transition_id" gorm:"primaryKey;autoIncrement;column:transition_id"`
+	MatchID      uint      `json:"match_id" gorm:"not null;index;column:match_id"`
+	FromStatus   string    `json:"from_status" gorm:"size:20;column:from_status"`
+	ToStatus     string    `json:"to_status" gorm:"not null;size:20;column:to_status"`
+	ActorID      *uint     `json:"actor_id,omitempty" gorm:"column:actor_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// MatchPair orders two user ids the way they are stored on a Match
+func MatchPair(a, b uint) (uint, uint) {
+	if a > b {
+		return b, a
+	}
+	return a, b
+}
+
+// OtherUser returns the participant of the match who is not userID
+func (m *Match) OtherUser(userID uint) uint {
+	if m.UserID1 == userID {
+		return m.UserID2
+	}
+	return m.UserID1
+}
+
+// HasParticipant reports whether userID is one of the two players in the match
+func (m *Match) HasParticipant(userID uint) bool {
+	return m.UserID1 == userID || m.UserID2 == userID
 }
@@ -5,8 +5,8 @@ import (
 )
 
 type Message struct {
-	MessageID uint       `json:"message_id" gorm:"primaryKey;autoIncrement;column:message_id"`
-	MatchID   uint       `json:"match_id" gorm:"not null;index;column:match_id"`
+	MessageID uint       `json:"message_id" gorm:"primaryKey;autoIncrement;index:idx_messages_match_message,priority:2;column:message_id"`
+	MatchID   uint       `json:"match_id" gorm:"not null;index:idx_messages_match_message,priority:1;column:match_id"`
 	SenderID  uint       `json:"sender_id" gorm:"not null;index;column:sender_id"`
 	Content   string     `json:"content" gorm:"not null;type:text"`
 	CreatedAt time.Time  `json:"created_at"`
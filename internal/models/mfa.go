@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// UserMFA holds a user's TOTP enrollment. EnabledAt stays nil until the user has
+// proven possession of the secret by confirming a code.
+type UserMFA struct {
+	UserID       uint       `json:"user_id" gorm:"primaryKey;autoIncrement:false;column:user_id"`
+	Secret       string     `json:"-" gorm:"not null;size:64"`
+	EnabledAt    *time.Time `json:"enabled_at,omitempty" gorm:"column:enabled_at"`
+	LastUsedStep int64      `json:"-" gorm:"not null;default:0;column:last_used_step"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// MFARecoveryCode is a single-use fallback code, stored as a SHA-256 hash
+type MFARecoveryCode struct {
+	CodeID    uint       `json:"code_id" gorm:"primaryKey;autoIncrement;column:code_id"`
+	UserID    uint       `json:"user_id" gorm:"not null;index;column:user_id"`
+	CodeHash  string     `json:"-" gorm:"not null;size:64;column:code_hash"`
+	UsedAt    *time.Time `json:"used_at,omitempty" gorm:"column:used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
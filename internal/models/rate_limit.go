@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// RateLimitBucket is the token bucket behind one rate limit key
+type RateLimitBucket struct {
+	Key        string    `json:"key" gorm:"primaryKey;size:255;column:bucket_key"`
+	Tokens     float64   `json:"tokens" gorm:"not null"`
+	RefilledAt time.Time `json:"refilled_at" gorm:"not null"`
+	// ExpiresAt is when the bucket is full again and can be dropped
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+}
+
+// LoginFailure counts the recent failed sign-ins of one account or login name
+type LoginFailure struct {
+	Key           string     `json:"key" gorm:"primaryKey;size:255;column:failure_key"`
+	Failures      int        `json:"failures" gorm:"not null"`
+	LastFailureAt time.Time  `json:"last_failure_at" gorm:"not null"`
+	LockedUntil   *time.Time `json:"locked_until"`
+	// ExpiresAt is when the failures are forgotten and the record can be dropped
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+// Recommendation is a precomputed entry in a user's recommendation feed
+type Recommendation struct {
+	UserID      uint               `json:"user_id" gorm:"primaryKey;autoIncrement:false;column:user_id"`
+	CandidateID uint               `json:"candidate_id" gorm:"primaryKey;autoIncrement:false;index;column:candidate_id"`
+	Position    int                `json:"position" gorm:"not null;column:position"`
+	Score       float64            `json:"score" gorm:"not null"`
+	Components  map[string]float64 `json:"components" gorm:"type:jsonb;serializer:json;column:components"`
+	ComputedAt  time.Time          `json:"computed_at" gorm:"not null;index;column:computed_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// RefreshToken is the server-side record of an issued refresh token. Tokens are
+// single-use: rotating one sets RotatedAt, and presenting a rotated token again
+// revokes every token sharing its FamilyID.
+type RefreshToken struct {
+	TokenID      string     `json:"token_id" gorm:"primaryKey;size:64;column:token_id"`
+	UserID       uint       `json:"user_id" gorm:"not null;index;column:user_id"`
+	FamilyID     string     `json:"family_id" gorm:"not null;index;size:64;column:family_id"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"not null;column:expires_at"`
+	RotatedAt    *time.Time `json:"rotated_at,omitempty" gorm:"column:rotated_at"`
+	ReplacedByID *string    `json:"replaced_by_id,omitempty" gorm:"size:64;column:replaced_by_id"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty" gorm:"column:revoked_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
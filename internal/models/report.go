@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+)
+
+// Report statuses
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusResolved  = "resolved"
+	ReportStatusDismissed = "dismissed"
+)
+
+// Report reasons a player can choose from
+const (
+	ReportReasonSpam          = "spam"
+	ReportReasonHarassment    = "harassment"
+	ReportReasonInappropriate = "inappropriate_content"
+	ReportReasonCheating      = "cheating"
+	ReportReasonImpersonation = "impersonation"
+	ReportReasonUnderage      = "underage"
+	ReportReasonOther         = "other"
+)
+
+// ReportReasons lists every reason a report may give
+func ReportReasons() []string {
+	return []string{
+		ReportReasonSpam,
+		ReportReasonHarassment,
+		ReportReasonInappropriate,
+		ReportReasonCheating,
+		ReportReasonImpersonation,
+		ReportReasonUnderage,
+		ReportReasonOther,
+	}
+}
+
+// Report is a player's complaint about another player, waiting in the
+// moderation queue until an admin resolves or dismisses it
+type Report struct {
+	ReportID   uint   `json:"report_id" gorm:"primaryKey;autoIncrement;index:idx_reports_status_report,priority:2;column:report_id"`
+	ReporterID uint   `json:"reporter_id" gorm:"not null;index;column:reporter_id"`
+	ReportedID uint   `json:"reported_id" gorm:"not null;index;column:reported_id"`
+	Reason     string `json:"reason" gorm:"not null;size:30"`
+	Details    string `json:"details,omitempty" gorm:"type:text"`
+	// Evidence is a copy of the reported messages, taken when the report is filed
+	// so that it survives the messages being deleted
+	Evidence   []ReportEvidence `json:"evidence" gorm:"type:jsonb;serializer:json"`
+	Status     string           `json:"status" gorm:"not null;size:20;default:'open';index:idx_reports_status_report,priority:1"`
+	ReviewerID *uint            `json:"reviewer_id,omitempty" gorm:"column:reviewer_id"`
+	ReviewedAt *time.Time       `json:"reviewed_at,omitempty" gorm:"column:reviewed_at"`
+	Resolution string           `json:"resolution,omitempty" gorm:"type:text"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+// ReportEvidence is a message attached to a report
+type ReportEvidence struct {
+	MessageID uint      `json:"message_id"`
+	MatchID   uint      `json:"match_id"`
+	SenderID  uint      `json:"sender_id"`
+	Content   string    `json:"content"`
+	SentAt    time.Time `json:"sent_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// RevokedToken is an access token revoked before its natural expiry, keyed by its jti
+type RevokedToken struct {
+	TokenID   string    `json:"token_id" gorm:"primaryKey;size:64;column:token_id"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index;column:expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TokenCutoff invalidates every access token issued to a user before RevokedBefore
+type TokenCutoff struct {
+	UserID        uint      `json:"user_id" gorm:"primaryKey;autoIncrement:false;column:user_id"`
+	RevokedBefore time.Time `json:"revoked_before" gorm:"not null;column:revoked_before"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
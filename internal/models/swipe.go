@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// Swipe actions
+const (
+	SwipeLike      = "like"
+	SwipePass      = "pass"
+	SwipeSuperLike = "super_like"
+)
+
+// Swipe is a player's latest decision about another player's profile
+type Swipe struct {
+	SwiperID  uint      `json:"swiper_id" gorm:"primaryKey;autoIncrement:false;column:swiper_id"`
+	TargetID  uint      `json:"target_id" gorm:"primaryKey;autoIncrement:false;index;column:target_id"`
+	Action    string    `json:"action" gorm:"not null;size:20"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"index"`
+}
@@ -7,28 +7,76 @@ import (
 )
 
 type User struct {
-	UserID       uint           `json:"user_id" gorm:"primaryKey;autoIncrement;column:user_id"`
-	Username     string         `json:"username" gorm:"uniqueIndex;not null;size:50"`
-	Email        string         `json:"email" gorm:"uniqueIndex;not null;size:100"`
-	PasswordHash string         `json:"-" gorm:"not null;column:password_hash"`
-	SoftDelete   bool           `json:"soft_delete" gorm:"default:false;column:soft_delete"`
-	AuthLevel    string         `json:"auth_level" gorm:"default:user;column:auth_level"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+	UserID          uint           `json:"user_id" gorm:"primaryKey;autoIncrement;column:user_id"`
+	Username        string         `json:"username" gorm:"uniqueIndex;not null;size:50"`
+	Email           string         `json:"email" gorm:"uniqueIndex;not null;size:100"`
+	PasswordHash    string         `json:"-" gorm:"not null;column:password_hash"`
+	SoftDelete      bool           `json:"soft_delete" gorm:"default:false;column:soft_delete"`
+	AuthLevel       string         `json:"auth_level" gorm:"default:user;column:auth_level"`
+	EmailVerifiedAt *time.Time     `json:"email_verified_at,omitempty" gorm:"column:email_verified_at"`
+	LastActiveAt    *time.Time     `json:"last_active_at,omitempty" gorm:"index;column:last_active_at"`
+	SuspendedUntil  *time.Time     `json:"suspended_until,omitempty" gorm:"index;column:suspended_until"`
+	BannedAt        *time.Time     `json:"banned_at,omitempty" gorm:"column:banned_at"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+}
+
+// IsBanned reports whether the account was banned permanently
+func (u *User) IsBanned() bool {
+	return u.BannedAt != nil
+}
+
+// IsSuspended reports whether the account is suspended at the given time
+func (u *User) IsSuspended(now time.Time) bool {
+	return u.SuspendedUntil != nil && now.Before(*u.SuspendedUntil)
 }
 
 type Profile struct {
-	ProfileID    uint              `json:"profile_id" gorm:"primaryKey;autoIncrement;column:profile_id"`
-	UserID       uint              `json:"user_id" gorm:"not null;index;column:user_id"`
-	FirstName    *string           `json:"first_name,omitempty" gorm:"size:50;column:first_name"`
-	LastName     *string           `json:"last_name,omitempty" gorm:"size:50;column:last_name"`
-	Gender       *string           `json:"gender,omitempty" gorm:"size:20"`
-	DateOfBirth  *time.Time        `json:"date_of_birth,omitempty" gorm:"column:date_of_birth"`
-	Bio          *string           `json:"bio,omitempty" gorm:"type:text"`
-	City         *string           `json:"city,omitempty" gorm:"size:100"`
-	Country      *string           `json:"country,omitempty" gorm:"size:100"`
-	GameRanks    map[string]string `json:"game_ranks,omitempty" gorm:"type:jsonb;column:game_ranks"`
-	CreatedAt    time.Time         `json:"created_at"`
-	UpdatedAt    time.Time         `json:"updated_at"`
+	ProfileID      uint              `json:"profile_id" gorm:"primaryKey;autoIncrement;column:profile_id"`
+	UserID         uint              `json:"user_id" gorm:"not null;index;column:user_id"`
+	FirstName      *string           `json:"first_name,omitempty" gorm:"size:50;column:first_name"`
+	LastName       *string           `json:"last_name,omitempty" gorm:"size:50;column:last_name"`
+	Gender         *string           `json:"gender,omitempty" gorm:"size:20;index"`
+	DateOfBirth    *time.Time        `json:"date_of_birth,omitempty" gorm:"index;column:date_of_birth"`
+	Bio            *string           `json:"bio,omitempty" gorm:"type:text"`
+	City           *string           `json:"city,omitempty" gorm:"size:100;index:idx_profiles_city,expression:lower(city)"`
+	Country        *string           `json:"country,omitempty" gorm:"size:100;index:idx_profiles_country,expression:lower(country)"`
+	GameRanks      map[string]string `json:"game_ranks,omitempty" gorm:"type:jsonb;serializer:json;index:idx_profiles_game_ranks,type:gin;column:game_ranks"`
+	GameRankValues map[string]int    `json:"game_rank_values,omitempty" gorm:"type:jsonb;serializer:json;column:game_rank_values"`
+	Latitude       *float64          `json:"latitude,omitempty" gorm:"column:latitude"`
+	Longitude      *float64          `json:"longitude,omitempty" gorm:"column:longitude"`
+	Availability   []string          `json:"availability,omitempty" gorm:"type:jsonb;serializer:json;column:availability"`
+	Privacy        ProfilePrivacy    `json:"privacy" gorm:"embedded;embeddedPrefix:privacy_"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// Visibility levels for profile fields shown to other players
+const (
+	VisibilityPublic  = "public"
+	VisibilityMatches = "matches"
+	VisibilityPrivate = "private"
+)
+
+// ProfilePrivacy controls who besides the owner can see each optional profile field
+type ProfilePrivacy struct {
+	FirstName string `json:"first_name" gorm:"size:10;not null;default:public"`
+	LastName  string `json:"last_name" gorm:"size:10;not null;default:matches"`
+	Gender    string `json:"gender" gorm:"size:10;not null;default:public"`
+	Age       string `json:"age" gorm:"size:10;not null;default:public"`
+	City      string `json:"city" gorm:"size:10;not null;default:public"`
+	Country   string `json:"country" gorm:"size:10;not null;default:public"`
+}
+
+// DefaultProfilePrivacy is applied to new profiles
+func DefaultProfilePrivacy() ProfilePrivacy {
+	return ProfilePrivacy{
+		FirstName: VisibilityPublic,
+		LastName:  VisibilityMatches,
+		Gender:    VisibilityPublic,
+		Age:       VisibilityPublic,
+		City:      VisibilityPublic,
+		Country:   VisibilityPublic,
+	}
 }
@@ -0,0 +1,106 @@
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MinLength is the minimum number of characters in a password
+	MinLength = 10
+	// MaxLength is the maximum length in bytes; bcrypt ignores anything past 72 bytes
+	MaxLength = 72
+)
+
+var (
+	ErrTooShort  = fmt.Errorf("password must be at least %d characters long", MinLength)
+	ErrTooLong   = fmt.Errorf("password must be at most %d bytes long", MaxLength)
+	ErrBreached  = errors.New("password is too common and appears in known breach lists")
+	ErrPersonal  = errors.New("password must not contain your username or email")
+	ErrRepeating = errors.New("password must not be a single repeated character")
+)
+
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+var commonPasswords = loadCommonPasswords(commonPasswordsFile)
+
+// Validate checks a candidate password against the strength rules. Personal values
+// such as the username or email are rejected as substrings of the password.
+func Validate(password string, personal ...string) error {
+	if utf8.RuneCountInString(password) < MinLength {
+		return ErrTooShort
+	}
+
+	if len(password) > MaxLength {
+		return ErrTooLong
+	}
+
+	if isRepeating(password) {
+		return ErrRepeating
+	}
+
+	if IsCommon(password) {
+		return ErrBreached
+	}
+
+	lower := strings.ToLower(password)
+	for _, value := range personal {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if at := strings.Index(value, "@"); at >= 0 {
+			value = value[:at]
+		}
+		if len(value) >= 3 && strings.Contains(lower, value) {
+			return ErrPersonal
+		}
+	}
+
+	return nil
+}
+
+// IsCommon reports whether the password, or the password with trailing digits and
+// symbols removed, appears in the bundled breached-password list
+func IsCommon(password string) bool {
+	lower := strings.ToLower(password)
+	if _, found := commonPasswords[lower]; found {
+		return true
+	}
+
+	base := strings.TrimRightFunc(lower, func(r rune) bool {
+		return unicode.IsDigit(r) || unicode.IsPunct(r) || unicode.IsSymbol(r)
+	})
+	if base == "" {
+		return false
+	}
+
+	_, found := commonPasswords[base]
+	return found
+}
+
+func isRepeating(password string) bool {
+	first, _ := utf8.DecodeRuneInString(password)
+	for _, r := range password {
+		if r != first {
+			return false
+		}
+	}
+	return true
+}
+
+func loadCommonPasswords(contents string) map[string]struct{} {
+	passwords := make(map[string]struct{})
+	scanner := bufio.NewScanner(strings.NewReader(contents))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords[strings.ToLower(line)] = struct{}{}
+	}
+	return passwords
+}
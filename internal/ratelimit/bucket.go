@@ -0,0 +1,138 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// Limit allows Requests per Period on average, in bursts of up to Requests. A
+// limit with no requests is disabled.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// PerMinute is a limit of n requests a minute
+func PerMinute(n int) Limit {
+	return Limit{Requests: n, Period: time.Minute}
+}
+
+// Enabled reports whether the limit restricts anything
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// rate is how many tokens the bucket regains per second
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Result is the state of a bucket after a request was counted against it
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is how long until the next request would be allowed, zero when
+	// the request was allowed
+	RetryAfter time.Duration
+}
+
+// bucket is a token bucket. It starts full, every request takes one token, and
+// tokens flow back at the limit's rate up to its burst size.
+type bucket struct {
+	tokens     float64
+	refilledAt time.Time
+}
+
+// newBucket returns a full bucket for the limit
+func newBucket(limit Limit, now time.Time) bucket {
+	return bucket{tokens: float64(limit.Requests), refilledAt: now}
+}
+
+// take refills the bucket for the time since it was last used and takes a
+// token from it when one is available
+func (b *bucket) take(limit Limit, now time.Time) Result {
+	burst := float64(limit.Requests)
+	if elapsed := now.Sub(b.refilledAt).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+elapsed*limit.rate())
+	}
+	b.refilledAt = now
+
+	result := Result{Limit: limit.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.tokens) / limit.rate())
+	}
+	result.Remaining = int(math.Floor(b.tokens))
+	result.Reset = seconds((burst - b.tokens) / limit.rate())
+	return result
+}
+
+// LockoutPolicy locks a key out after Threshold failures, for BaseDelay at
+// first and twice as long after every further failure, up to MaxDelay when
+// that is set.
+// Failures are forgotten once none has happened for Window. A policy with no
+// threshold is disabled.
+type LockoutPolicy struct {
+	Threshold int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	Window    time.Duration
+}
+
+// Enabled reports whether the policy ever locks a key
+func (p LockoutPolicy) Enabled() bool {
+	return p.Threshold > 0 && p.BaseDelay > 0
+}
+
+// delay is how long a key is locked after its given number of failures
+func (p LockoutPolicy) delay(failures int) time.Duration {
+	if failures < p.Threshold {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := p.Threshold; i < failures && (p.MaxDelay <= 0 || delay < p.MaxDelay) && delay < math.MaxInt64/2; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// failureRecord counts the recent failures of one key
+type failureRecord struct {
+	failures      int
+	lastFailureAt time.Time
+	lockedUntil   time.Time
+}
+
+// fail counts another failure and extends the lockout when the policy calls for one
+func (r *failureRecord) fail(policy LockoutPolicy, now time.Time) {
+	if now.Sub(r.lastFailureAt) > policy.Window {
+		r.failures = 0
+	}
+	r.failures++
+	r.lastFailureAt = now
+	if delay := policy.delay(r.failures); delay > 0 {
+		r.lockedUntil = now.Add(delay)
+	}
+}
+
+// expiry is when the record stops affecting anything and can be dropped
+func (r *failureRecord) expiry(policy LockoutPolicy) time.Time {
+	expires := r.lastFailureAt.Add(policy.Window)
+	if r.lockedUntil.After(expires) {
+		return r.lockedUntil
+	}
+	return expires
+}
+
+// seconds converts fractional seconds to a duration
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
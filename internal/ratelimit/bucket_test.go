@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockoutPolicyDelay(t *testing.T) {
+	capped := LockoutPolicy{Threshold: 3, BaseDelay: 30 * time.Second, MaxDelay: 5 * time.Minute, Window: time.Hour}
+	uncapped := LockoutPolicy{Threshold: 3, BaseDelay: 30 * time.Second, Window: time.Hour}
+
+	tests := []struct {
+		name     string
+		policy   LockoutPolicy
+		failures int
+		want     time.Duration
+	}{
+		{"below the threshold", capped, 2, 0},
+		{"at the threshold", capped, 3, 30 * time.Second},
+		{"one failure past the threshold", capped, 4, time.Minute},
+		{"two failures past the threshold", capped, 5, 2 * time.Minute},
+		{"three failures past the threshold", capped, 6, 4 * time.Minute},
+		{"doubling reaches the cap", capped, 7, 5 * time.Minute},
+		{"stays at the cap", capped, 20, 5 * time.Minute},
+		{"without a cap keeps doubling", uncapped, 7, 8 * time.Minute},
+		{"cap below the base delay", LockoutPolicy{Threshold: 1, BaseDelay: time.Minute, MaxDelay: 10 * time.Second}, 1, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.delay(tt.failures)
+			if got != tt.want {
+				t.Errorf("delay(%d) = %v, want %v", tt.failures, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLockoutPolicyDelayWithoutCapDoesNotOverflow(t *testing.T) {
+	policy := LockoutPolicy{Threshold: 1, BaseDelay: time.Second, Window: time.Hour}
+	previous := policy.delay(1)
+	for failures := 2; failures <= 200; failures++ {
+		got := policy.delay(failures)
+		if got < previous {
+			t.Fatalf("delay(%d) = %v, shorter than delay(%d) = %v", failures, got, failures-1, previous)
+		}
+		previous = got
+	}
+}
+
+func TestFailureRecordLocksProgressively(t *testing.T) {
+	policy := LockoutPolicy{Threshold: 2, BaseDelay: time.Minute, MaxDelay: 3 * time.Minute, Window: time.Hour}
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	var record failureRecord
+	wantLocks := []time.Duration{0, time.Minute, 2 * time.Minute, 3 * time.Minute, 3 * time.Minute}
+	for i, want := range wantLocks {
+		now := start.Add(time.Duration(i) * time.Second)
+		record.fail(policy, now)
+
+		var got time.Duration
+		if record.lockedUntil.After(now) {
+			got = record.lockedUntil.Sub(now)
+		}
+		if got != want {
+			t.Errorf("failure %d: locked for %v, want %v", i+1, got, want)
+		}
+	}
+
+	// Failures are forgotten once the window passes without one
+	later := start.Add(2 * time.Hour)
+	record.fail(policy, later)
+	if record.failures != 1 {
+		t.Errorf("failures after the window = %d, want 1", record.failures)
+	}
+	if record.lockedUntil.After(later) {
+		t.Errorf("locked until %v after the window, want no lock", record.lockedUntil)
+	}
+}
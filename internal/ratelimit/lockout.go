@@ -0,0 +1,49 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Lockout locks keys, such as an account being signed in to, out for longer
+// and longer after repeated failures
+type Lockout struct {
+	store  Store
+	policy LockoutPolicy
+}
+
+func NewLockout(store Store, policy LockoutPolicy) *Lockout {
+	return &Lockout{store: store, policy: policy}
+}
+
+// Check returns how long the key stays locked, or zero when it may be tried
+func (l *Lockout) Check(ctx context.Context, key string) (time.Duration, error) {
+	if !l.policy.Enabled() {
+		return 0, nil
+	}
+	lockedUntil, err := l.store.LockedUntil(ctx, key)
+	if err != nil || lockedUntil.IsZero() {
+		return 0, err
+	}
+	return time.Until(lockedUntil), nil
+}
+
+// Fail records a failed attempt and returns how long the key is now locked
+func (l *Lockout) Fail(ctx context.Context, key string) (time.Duration, error) {
+	if !l.policy.Enabled() {
+		return 0, nil
+	}
+	lockedUntil, err := l.store.Fail(ctx, key, l.policy)
+	if err != nil || lockedUntil.IsZero() {
+		return 0, err
+	}
+	return time.Until(lockedUntil), nil
+}
+
+// Clear forgets the key's failures after a successful attempt
+func (l *Lockout) Clear(ctx context.Context, key string) error {
+	if !l.policy.Enabled() {
+		return nil
+	}
+	return l.store.Clear(ctx, key)
+}
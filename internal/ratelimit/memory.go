@@ -0,0 +1,111 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-process Store. Counts are lost on restart and every
+// instance limits on its own, so with several instances a client gets each
+// limit once per instance.
+type MemoryStore struct {
+	mu       sync.Mutex
+	buckets  map[string]*memoryBucket
+	failures map[string]*memoryFailures
+}
+
+type memoryBucket struct {
+	bucket
+	expiresAt time.Time
+}
+
+type memoryFailures struct {
+	failureRecord
+	expiresAt time.Time
+}
+
+// NewMemoryStore creates an empty in-memory rate limit store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:  make(map[string]*memoryBucket),
+		failures: make(map[string]*memoryFailures),
+	}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &memoryBucket{bucket: newBucket(limit, now)}
+		s.buckets[key] = b
+	}
+
+	result := b.take(limit, now)
+	b.expiresAt = now.Add(result.Reset)
+	return result, nil
+}
+
+func (s *MemoryStore) Fail(ctx context.Context, key string, policy LockoutPolicy) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	record, ok := s.failures[key]
+	if !ok {
+		record = &memoryFailures{}
+		s.failures[key] = record
+	}
+
+	record.fail(policy, now)
+	record.expiresAt = record.expiry(policy)
+	return activeLock(record.lockedUntil, now), nil
+}
+
+func (s *MemoryStore) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.failures[key]
+	if !ok {
+		return time.Time{}, nil
+	}
+	return activeLock(record.lockedUntil, time.Now()), nil
+}
+
+func (s *MemoryStore) Clear(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.failures, key)
+	return nil
+}
+
+func (s *MemoryStore) Prune(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, b := range s.buckets {
+		if now.After(b.expiresAt) {
+			delete(s.buckets, key)
+		}
+	}
+	for key, record := range s.failures {
+		if now.After(record.expiresAt) {
+			delete(s.failures, key)
+		}
+	}
+	return nil
+}
+
+// activeLock returns lockedUntil when it is still in the future, otherwise zero
+func activeLock(lockedUntil, now time.Time) time.Time {
+	if lockedUntil.After(now) {
+		return lockedUntil
+	}
+	return time.Time{}
+}
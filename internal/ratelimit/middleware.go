@@ -0,0 +1,122 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// KeyFunc names the bucket a request is counted against
+type KeyFunc func(c *gin.Context) string
+
+// ByIP counts requests per client IP
+func ByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByUser counts requests per authenticated user, falling back to the client IP
+// when the request carries no user. It must run after the auth middleware.
+func ByUser(c *gin.Context) string {
+	if userID, _, _, exists := middleware.GetUserFromContext(c); exists {
+		return UserKey(userID)
+	}
+	return ByIP(c)
+}
+
+// ByRoute counts requests per route, shared by every client
+func ByRoute(c *gin.Context) string {
+	return "route:" + c.Request.Method + " " + c.FullPath()
+}
+
+// UserKey is the key ByUser uses for a user, for counting requests that do not
+// arrive over HTTP against the same bucket
+func UserKey(userID uint) string {
+	return "user:" + strconv.FormatUint(uint64(userID), 10)
+}
+
+// Limiter applies one limit to the requests of a route group. Limiters with
+// different names keep separate buckets in the same store.
+type Limiter struct {
+	name  string
+	store Store
+	limit Limit
+	key   KeyFunc
+}
+
+func NewLimiter(store Store, name string, limit Limit, key KeyFunc) *Limiter {
+	return &Limiter{
+		name:  name,
+		store: store,
+		limit: limit,
+		key:   key,
+	}
+}
+
+// Take counts a request against the bucket for key. Disabled limits allow
+// everything.
+func (l *Limiter) Take(ctx context.Context, key string) (Result, error) {
+	if !l.limit.Enabled() {
+		return Result{Allowed: true}, nil
+	}
+	return l.store.Take(ctx, l.name+":"+key, l.limit)
+}
+
+// Middleware rejects requests over the limit with 429 and describes the limit
+// in RateLimit-* headers. When the store fails the request is let through, so
+// an outage of the store does not take the API down with it.
+func (l *Limiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !l.limit.Enabled() {
+			c.Next()
+			return
+		}
+
+		result, err := l.Take(c.Request.Context(), l.key(c))
+		if err != nil {
+			log.Printf("Failed to check %s rate limit: %v", l.name, err)
+			c.Next()
+			return
+		}
+
+		setHeaders(c, l.limit, result)
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":       "Too many requests, please slow down",
+				"retry_after": retryAfter,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// setHeaders writes the RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset
+// and RateLimit-Policy headers. When several limits apply to a request, the
+// one closest to running out is described.
+func setHeaders(c *gin.Context, limit Limit, result Result) {
+	if current := c.Writer.Header().Get("RateLimit-Remaining"); current != "" {
+		if remaining, err := strconv.Atoi(current); err == nil && remaining <= result.Remaining {
+			return
+		}
+	}
+
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+	c.Header("RateLimit-Policy", strconv.Itoa(limit.Requests)+";w="+strconv.Itoa(ceilSeconds(limit.Period)))
+}
+
+// ceilSeconds rounds a duration up to whole seconds
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/database"
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresStore is a Store backed by the rate_limit_buckets and login_failures
+// tables, so limits are shared by every instance of the API. Each change locks
+// the key's row for the length of its transaction.
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+// NewPostgresStore creates a rate limit store on top of the application database
+func NewPostgresStore(db *database.Database) *PostgresStore {
+	return &PostgresStore{db: db.GetDB()}
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	var result Result
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		full := newBucket(limit, now)
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.RateLimitBucket{
+			Key:        key,
+			Tokens:     full.tokens,
+			RefilledAt: full.refilledAt,
+			ExpiresAt:  now,
+		}).Error; err != nil {
+			return err
+		}
+
+		var row models.RateLimitBucket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("bucket_key = ?", key).First(&row).Error; err != nil {
+			return err
+		}
+
+		b := bucket{tokens: row.Tokens, refilledAt: row.RefilledAt}
+		result = b.take(limit, now)
+		return tx.Model(&models.RateLimitBucket{}).Where("bucket_key = ?", key).Updates(map[string]interface{}{
+			"tokens":      b.tokens,
+			"refilled_at": b.refilledAt,
+			"expires_at":  now.Add(result.Reset),
+		}).Error
+	})
+	return result, err
+}
+
+func (s *PostgresStore) Fail(ctx context.Context, key string, policy LockoutPolicy) (time.Time, error) {
+	var lockedUntil time.Time
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.LoginFailure{
+			Key:       key,
+			ExpiresAt: now,
+		}).Error; err != nil {
+			return err
+		}
+
+		var row models.LoginFailure
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("failure_key = ?", key).First(&row).Error; err != nil {
+			return err
+		}
+
+		record := failureRecord{failures: row.Failures, lastFailureAt: row.LastFailureAt}
+		if row.LockedUntil != nil {
+			record.lockedUntil = *row.LockedUntil
+		}
+		record.fail(policy, now)
+
+		updates := map[string]interface{}{
+			"failures":        record.failures,
+			"last_failure_at": record.lastFailureAt,
+			"expires_at":      record.expiry(policy),
+		}
+		if !record.lockedUntil.IsZero() {
+			updates["locked_until"] = record.lockedUntil
+		}
+		lockedUntil = activeLock(record.lockedUntil, now)
+		return tx.Model(&models.LoginFailure{}).Where("failure_key = ?", key).Updates(updates).Error
+	})
+	return lockedUntil, err
+}
+
+func (s *PostgresStore) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	var rows []models.LoginFailure
+	if err := s.db.WithContext(ctx).Where("failure_key = ?", key).Limit(1).Find(&rows).Error; err != nil {
+		return time.Time{}, err
+	}
+	if len(rows) == 0 || rows[0].LockedUntil == nil {
+		return time.Time{}, nil
+	}
+	return activeLock(*rows[0].LockedUntil, time.Now()), nil
+}
+
+func (s *PostgresStore) Clear(ctx context.Context, key string) error {
+	return s.db.WithContext(ctx).Where("failure_key = ?", key).Delete(&models.LoginFailure{}).Error
+}
+
+func (s *PostgresStore) Prune(ctx context.Context) error {
+	db := s.db.WithContext(ctx)
+	now := time.Now()
+
+	if err := db.Where("expires_at < ?", now).Delete(&models.RateLimitBucket{}).Error; err != nil {
+		return err
+	}
+	return db.Where("expires_at < ?", now).Delete(&models.LoginFailure{}).Error
+}
@@ -0,0 +1,38 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/database"
+)
+
+// Store keeps the token buckets and failure counts behind rate limits and
+// lockouts. Every method applies its change atomically, so limits hold when
+// several requests for the same key arrive at once.
+type Store interface {
+	// Take counts a request against the bucket stored under key
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+	// Fail records a failed attempt under key and returns when the key's
+	// lockout ends, which is zero when it is not locked
+	Fail(ctx context.Context, key string, policy LockoutPolicy) (time.Time, error)
+	// LockedUntil returns when the key's lockout ends, or zero when it is not locked
+	LockedUntil(ctx context.Context, key string) (time.Time, error)
+	// Clear forgets the failures recorded under key
+	Clear(ctx context.Context, key string) error
+	// Prune drops full buckets and failure records that have expired
+	Prune(ctx context.Context) error
+}
+
+// NewStore creates the store implementation selected by kind ("memory" or "postgres")
+func NewStore(kind string, db *database.Database) (Store, error) {
+	switch kind {
+	case "memory":
+		return NewMemoryStore(), nil
+	case "postgres":
+		return NewPostgresStore(db), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit store: %s", kind)
+	}
+}
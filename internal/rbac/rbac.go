@@ -0,0 +1,200 @@
+package rbac
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Permissions referenced by the API. The policy file decides which roles hold them.
+const (
+	PermAccountManage       = "account:manage"
+	PermProfileRead         = "profile:read"
+	PermProfileWrite        = "profile:write"
+	PermPlayersRead         = "players:read"
+	PermMatchesUse          = "matches:use"
+	PermMessagesSend        = "messages:send"
+	PermAdminAccess         = "admin:access"
+	PermUsersRead           = "users:read"
+	PermUsersSuspend        = "users:suspend"
+	PermUsersBan            = "users:ban"
+	PermMessagesModerate    = "messages:moderate"
+	PermReportsReview       = "reports:review"
+	PermUsersRestore        = "users:restore"
+	PermRolesAssign         = "roles:assign"
+	PermAuditRead           = "audit:read"
+	PermRolesAssignEngineer = "roles:assign_engineer"
+	PermRBACInspect         = "rbac:inspect"
+)
+
+//go:embed default_policy.json
+var defaultPolicy []byte
+
+// roleDefinition is a role as written in the policy file
+type roleDefinition struct {
+	Name        string   `json:"name"`
+	Inherits    []string `json:"inherits"`
+	Permissions []string `json:"permissions"`
+}
+
+// RoleSummary describes a role and the permissions it effectively holds
+type RoleSummary struct {
+	Role        string   `json:"role"`
+	Rank        int      `json:"rank"`
+	Inherits    []string `json:"inherits"`
+	Granted     []string `json:"granted"`
+	Permissions []string `json:"permissions"`
+}
+
+// Policy is the role hierarchy and permission model. Roles are listed from least
+// to most privileged, and that order is the hierarchy used to compare roles.
+type Policy struct {
+	roles     []roleDefinition
+	rank      map[string]int
+	effective map[string]map[string]bool
+}
+
+// Load reads a policy file, or the bundled default policy when path is empty
+func Load(path string) (*Policy, error) {
+	contents := defaultPolicy
+	if path != "" {
+		var err error
+		contents, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read RBAC policy: %w", err)
+		}
+	}
+
+	var file struct {
+		Roles []roleDefinition `json:"roles"`
+	}
+	if err := json.Unmarshal(contents, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse RBAC policy: %w", err)
+	}
+
+	return newPolicy(file.Roles)
+}
+
+func newPolicy(roles []roleDefinition) (*Policy, error) {
+	if len(roles) == 0 {
+		return nil, fmt.Errorf("RBAC policy defines no roles")
+	}
+
+	p := &Policy{
+		roles:     roles,
+		rank:      make(map[string]int, len(roles)),
+		effective: make(map[string]map[string]bool, len(roles)),
+	}
+
+	for i, role := range roles {
+		if role.Name == "" {
+			return nil, fmt.Errorf("RBAC policy role %d has no name", i)
+		}
+		if _, exists := p.rank[role.Name]; exists {
+			return nil, fmt.Errorf("RBAC policy defines role %q twice", role.Name)
+		}
+		p.rank[role.Name] = i
+	}
+
+	for _, role := range roles {
+		permissions, err := p.resolve(role.Name, map[string]bool{})
+		if err != nil {
+			return nil, err
+		}
+		p.effective[role.Name] = permissions
+	}
+
+	return p, nil
+}
+
+// resolve collects a role's own and inherited permissions, rejecting cycles
+func (p *Policy) resolve(name string, visiting map[string]bool) (map[string]bool, error) {
+	if visiting[name] {
+		return nil, fmt.Errorf("RBAC policy has an inheritance cycle through role %q", name)
+	}
+	visiting[name] = true
+	defer delete(visiting, name)
+
+	index, ok := p.rank[name]
+	if !ok {
+		return nil, fmt.Errorf("RBAC policy inherits from unknown role %q", name)
+	}
+
+	role := p.roles[index]
+	permissions := make(map[string]bool, len(role.Permissions))
+	for _, permission := range role.Permissions {
+		permissions[permission] = true
+	}
+
+	for _, parent := range role.Inherits {
+		inherited, err := p.resolve(parent, visiting)
+		if err != nil {
+			return nil, err
+		}
+		for permission := range inherited {
+			permissions[permission] = true
+		}
+	}
+
+	return permissions, nil
+}
+
+// HasRole reports whether the role is defined by the policy
+func (p *Policy) HasRole(role string) bool {
+	_, ok := p.rank[role]
+	return ok
+}
+
+// HasPermission reports whether the role holds the permission, directly or by inheritance
+func (p *Policy) HasPermission(role, permission string) bool {
+	return p.effective[role][permission]
+}
+
+// HasAll reports whether the role holds every one of the permissions
+func (p *Policy) HasAll(role string, permissions ...string) bool {
+	for _, permission := range permissions {
+		if !p.HasPermission(role, permission) {
+			return false
+		}
+	}
+	return true
+}
+
+// Outranks reports whether role a sits strictly above role b in the hierarchy
+func (p *Policy) Outranks(a, b string) bool {
+	rankA, okA := p.rank[a]
+	rankB, okB := p.rank[b]
+	return okA && okB && rankA > rankB
+}
+
+// Roles lists the defined roles from least to most privileged
+func (p *Policy) Roles() []string {
+	names := make([]string, len(p.roles))
+	for i, role := range p.roles {
+		names[i] = role.Name
+	}
+	return names
+}
+
+// Matrix returns the effective permissions of every role
+func (p *Policy) Matrix() []RoleSummary {
+	summaries := make([]RoleSummary, 0, len(p.roles))
+	for i, role := range p.roles {
+		permissions := make([]string, 0, len(p.effective[role.Name]))
+		for permission := range p.effective[role.Name] {
+			permissions = append(permissions, permission)
+		}
+		sort.Strings(permissions)
+
+		summaries = append(summaries, RoleSummary{
+			Role:        role.Name,
+			Rank:        i,
+			Inherits:    append([]string{}, role.Inherits...),
+			Granted:     append([]string{}, role.Permissions...),
+			Permissions: permissions,
+		})
+	}
+	return summaries
+}
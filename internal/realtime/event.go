@@ -0,0 +1,74 @@
+package realtime
+
+import (
+	"context"
+)
+
+// Event types delivered to connected players
+const (
+	EventMessageCreated = "message.created"
+	EventMessagesRead   = "message.read"
+	EventTyping         = "typing"
+	EventMatchCreated   = "match.created"
+	EventMatchUpdated   = "match.status_changed"
+	// EventReset tells a stream client that events were lost and it should
+	// reload its matches and messages
+	EventReset = "reset"
+	EventError = "error"
+)
+
+// Event is a notification pushed to a player. UserID is the player who caused
+// it, when there is one.
+type Event struct {
+	Type    string      `json:"type"`
+	MatchID uint        `json:"match_id,omitempty"`
+	UserID  uint        `json:"user_id,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// Publisher delivers events to every live connection of the given users
+type Publisher interface {
+	Publish(userIDs []uint, event Event)
+}
+
+// Inbound is a request sent by a player over their connection
+type Inbound struct {
+	Type          string `json:"type"`
+	MatchID       uint   `json:"match_id"`
+	Content       string `json:"content,omitempty"`
+	UpToMessageID uint   `json:"up_to_message_id,omitempty"`
+}
+
+// InboundHandler acts on a request from userID. A returned error is sent back
+// to that connection as an error event.
+type InboundHandler func(ctx context.Context, userID uint, inbound Inbound) error
+
+// Publishers delivers every event to each of its publishers
+type Publishers []Publisher
+
+func (p Publishers) Publish(userIDs []uint, event Event) {
+	for _, publisher := range p {
+		publisher.Publish(userIDs, event)
+	}
+}
+
+// OnlyTypes wraps a publisher so that it only receives events of the given
+// types, such as to keep transient events out of a bounded log
+func OnlyTypes(publisher Publisher, types ...string) Publisher {
+	allowed := make(map[string]bool, len(types))
+	for _, eventType := range types {
+		allowed[eventType] = true
+	}
+	return typeFilter{publisher: publisher, allowed: allowed}
+}
+
+type typeFilter struct {
+	publisher Publisher
+	allowed   map[string]bool
+}
+
+func (f typeFilter) Publish(userIDs []uint, event Event) {
+	if f.allowed[event.Type] {
+		f.publisher.Publish(userIDs, event)
+	}
+}
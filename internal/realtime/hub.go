@@ -0,0 +1,251 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// writeWait is how long a single write to a connection may take
+	writeWait = 10 * time.Second
+	// pongWait is how long a connection may stay silent before it is dropped
+	pongWait = 60 * time.Second
+	// pingPeriod must be shorter than pongWait so pongs arrive in time
+	pingPeriod = 50 * time.Second
+	// maxInboundSize caps a single message from a client
+	maxInboundSize = 8 * 1024
+	// sendBuffer is how many events may queue for a connection before it is
+	// considered too slow and disconnected
+	sendBuffer = 64
+)
+
+// Hub tracks the live WebSocket connections of every player and fans events
+// out to them
+type Hub struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.RWMutex
+	clients map[uint]map[*client]struct{}
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// client is one WebSocket connection of a player
+type client struct {
+	hub    *Hub
+	conn   *websocket.Conn
+	userID uint
+	send   chan []byte
+
+	closeOnce sync.Once
+	done      chan struct{}
+	// closeCode and closeReason are sent in the close frame when done is closed
+	closeCode   int
+	closeReason string
+}
+
+func NewHub() *Hub {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Hub{
+		ctx:     ctx,
+		cancel:  cancel,
+		clients: make(map[uint]map[*client]struct{}),
+	}
+}
+
+// Publish queues the event on every connection of the given users. Connections
+// whose send buffer is full are disconnected rather than blocking the sender;
+// clients reload over the REST API when they reconnect.
+func (h *Hub) Publish(userIDs []uint, event Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to encode %s event: %v", event.Type, err)
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, userID := range userIDs {
+		for c := range h.clients[userID] {
+			c.enqueue(payload)
+		}
+	}
+}
+
+// Serve runs the connection until the client goes away or the hub closes. It
+// takes ownership of conn and blocks until the connection is finished.
+func (h *Hub) Serve(conn *websocket.Conn, userID uint, handle InboundHandler) {
+	c := &client{
+		hub:    h,
+		conn:   conn,
+		userID: userID,
+		send:   make(chan []byte, sendBuffer),
+		done:   make(chan struct{}),
+	}
+
+	if !h.register(c) {
+		_ = conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"),
+			time.Now().Add(writeWait))
+		conn.Close()
+		return
+	}
+	defer h.unregister(c)
+
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		c.writePump()
+	}()
+
+	c.readPump(handle)
+}
+
+// Disconnect closes every connection of the user, such as when their account is
+// suspended
+func (h *Hub) Disconnect(userID uint) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for c := range h.clients[userID] {
+		c.close(websocket.ClosePolicyViolation, "account is no longer active")
+	}
+}
+
+// Close disconnects every client and waits for their connections to finish
+func (h *Hub) Close() {
+	h.mu.Lock()
+	h.closed = true
+	for _, clients := range h.clients {
+		for c := range clients {
+			c.close(websocket.CloseGoingAway, "server shutting down")
+		}
+	}
+	h.mu.Unlock()
+
+	h.cancel()
+	h.wg.Wait()
+}
+
+func (h *Hub) register(c *client) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return false
+	}
+	if h.clients[c.userID] == nil {
+		h.clients[c.userID] = make(map[*client]struct{})
+	}
+	h.clients[c.userID][c] = struct{}{}
+	return true
+}
+
+func (h *Hub) unregister(c *client) {
+	c.close(websocket.CloseNormalClosure, "")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.clients[c.userID], c)
+	if len(h.clients[c.userID]) == 0 {
+		delete(h.clients, c.userID)
+	}
+}
+
+// enqueue adds a payload to the send buffer, disconnecting the client when the
+// buffer is full
+func (c *client) enqueue(payload []byte) {
+	select {
+	case <-c.done:
+	case c.send <- payload:
+	default:
+		c.close(websocket.ClosePolicyViolation, "client is not reading fast enough")
+	}
+}
+
+// close asks the write pump to send a close frame and shut the connection down
+func (c *client) close(code int, reason string) {
+	c.closeOnce.Do(func() {
+		c.closeCode = code
+		c.closeReason = reason
+		close(c.done)
+	})
+}
+
+// readPump handles requests from the client until the connection fails. Pongs
+// from the client extend the read deadline.
+func (c *client) readPump(handle InboundHandler) {
+	c.conn.SetReadLimit(maxInboundSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		var inbound Inbound
+		if err := c.conn.ReadJSON(&inbound); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				c.reply(Event{Type: EventError, Data: map[string]string{"error": "Invalid JSON format"}})
+				continue
+			}
+			return
+		}
+
+		if err := handle(c.hub.ctx, c.userID, inbound); err != nil {
+			c.reply(Event{Type: EventError, MatchID: inbound.MatchID, Data: map[string]string{"error": err.Error()}})
+		}
+	}
+}
+
+// reply sends an event to this connection only
+func (c *client) reply(event Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	c.enqueue(payload)
+}
+
+// writePump is the only goroutine that writes to the connection. It delivers
+// queued events, pings the client and sends the close frame on shutdown.
+func (c *client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case payload := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-c.done:
+			if c.closeCode != websocket.CloseAbnormalClosure {
+				_ = c.conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(c.closeCode, c.closeReason),
+					time.Now().Add(writeWait))
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package realtime
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StreamEvent is an event in a player's stream log. ID is sent to the client,
+// which passes it back as Last-Event-ID when it reconnects.
+type StreamEvent struct {
+	ID    string
+	Event Event
+}
+
+// Stream keeps a bounded log of each player's recent events so that clients of
+// the event stream can resume where they left off after reconnecting. Event IDs
+// carry the time the stream started, so IDs handed out before a restart are
+// recognized as unknown rather than matched against new events.
+type Stream struct {
+	size      int
+	retention time.Duration
+	epoch     string
+
+	mu   sync.Mutex
+	seq  uint64
+	logs map[uint]*eventLog
+	// floor is the newest event dropped along with a pruned log; a player without
+	// a log who last saw an older event may have missed it
+	floor  uint64
+	closed bool
+}
+
+// eventLog is one player's recent events, oldest first
+type eventLog struct {
+	events []sequencedEvent
+	// trimmed is the newest event that no longer fits in the log
+	trimmed     uint64
+	updatedAt   time.Time
+	subscribers map[*Subscription]struct{}
+}
+
+type sequencedEvent struct {
+	seq   uint64
+	event Event
+}
+
+// Subscription wakes a connected client when new events are logged for it
+type Subscription struct {
+	C <-chan struct{}
+
+	stream  *Stream
+	userID  uint
+	notify  chan struct{}
+	done    chan struct{}
+	endOnce sync.Once
+}
+
+// NewStream keeps up to size events per player. Logs of players without a live
+// connection are dropped by Prune once they have been idle for retention.
+func NewStream(size int, retention time.Duration) *Stream {
+	return &Stream{
+		size:      size,
+		retention: retention,
+		epoch:     strconv.FormatInt(time.Now().UnixNano(), 36),
+		logs:      make(map[uint]*eventLog),
+	}
+}
+
+// Publish appends the event to the log of each user and wakes their subscribers
+func (s *Stream) Publish(userIDs []uint, event Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	now := time.Now()
+	for _, userID := range userIDs {
+		log := s.log(userID)
+		if len(log.events) >= s.size {
+			log.trimmed = log.events[0].seq
+			log.events = append(log.events[:0], log.events[1:]...)
+		}
+		log.events = append(log.events, sequencedEvent{seq: s.seq, event: event})
+		log.updatedAt = now
+
+		for sub := range log.subscribers {
+			select {
+			case sub.notify <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// Subscribe registers a client of the user. Subscribe before reading the log
+// with Since so that no event can slip in between.
+func (s *Stream) Subscribe(userID uint) *Subscription {
+	notify := make(chan struct{}, 1)
+	sub := &Subscription{C: notify, stream: s, userID: userID, notify: notify, done: make(chan struct{})}
+
+	s.mu.Lock()
+	if s.closed {
+		sub.end()
+	} else {
+		s.log(userID).subscribers[sub] = struct{}{}
+	}
+	s.mu.Unlock()
+
+	return sub
+}
+
+// Done is closed when the client should disconnect, because the stream shut
+// down or the user was disconnected
+func (sub *Subscription) Done() <-chan struct{} {
+	return sub.done
+}
+
+// Close stops the subscription from being woken
+func (sub *Subscription) Close() {
+	s := sub.stream
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if log, ok := s.logs[sub.userID]; ok {
+		delete(log.subscribers, sub)
+	}
+	sub.end()
+}
+
+func (sub *Subscription) end() {
+	sub.endOnce.Do(func() {
+		close(sub.done)
+	})
+}
+
+// Since returns the user's events logged after lastEventID and the ID the client
+// is at once it has received them. complete is false when events may have been
+// lost, because lastEventID is unknown or older than the log; the client should
+// then reload its state. An empty lastEventID starts from the current event.
+func (s *Stream) Since(userID uint, lastEventID string) (events []StreamEvent, next string, complete bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next = s.formatID(s.seq)
+	if lastEventID == "" {
+		return nil, next, true
+	}
+
+	after, ok := s.parseID(lastEventID)
+	if !ok || after > s.seq {
+		return nil, next, false
+	}
+
+	log, ok := s.logs[userID]
+	if !ok {
+		return nil, next, after >= s.floor
+	}
+	if after < log.trimmed {
+		return nil, next, false
+	}
+
+	for _, logged := range log.events {
+		if logged.seq > after {
+			events = append(events, StreamEvent{ID: s.formatID(logged.seq), Event: logged.event})
+		}
+	}
+	return events, next, true
+}
+
+// Prune drops the logs of players without a live connection that have not
+// received an event within the retention period
+func (s *Stream) Prune(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().Add(-s.retention)
+	for userID, log := range s.logs {
+		if len(log.subscribers) > 0 || log.updatedAt.After(cutoff) {
+			continue
+		}
+		if len(log.events) > 0 && log.events[len(log.events)-1].seq > s.floor {
+			s.floor = log.events[len(log.events)-1].seq
+		}
+		delete(s.logs, userID)
+	}
+	return nil
+}
+
+// Disconnect tells every connected client of the user to finish
+func (s *Stream) Disconnect(userID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if log, ok := s.logs[userID]; ok {
+		for sub := range log.subscribers {
+			sub.end()
+		}
+	}
+}
+
+// Close tells every connected client to finish
+func (s *Stream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, log := range s.logs {
+		for sub := range log.subscribers {
+			sub.end()
+		}
+	}
+	s.closed = true
+}
+
+// log returns the user's log, creating it when needed. The caller holds s.mu.
+func (s *Stream) log(userID uint) *eventLog {
+	log, ok := s.logs[userID]
+	if !ok {
+		// Events of an earlier, pruned log are gone, so resuming from before the
+		// floor is never complete
+		log = &eventLog{
+			trimmed:     s.floor,
+			updatedAt:   time.Now(),
+			subscribers: make(map[*Subscription]struct{}),
+		}
+		s.logs[userID] = log
+	}
+	return log
+}
+
+func (s *Stream) formatID(seq uint64) string {
+	return s.epoch + "-" + strconv.FormatUint(seq, 10)
+}
+
+func (s *Stream) parseID(id string) (uint64, bool) {
+	epoch, seq, ok := strings.Cut(id, "-")
+	if !ok || epoch != s.epoch {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,271 @@
+package recommendations
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/config"
+	"github.com/1shoukr/swiftplay-backend/internal/games"
+	"github.com/1shoukr/swiftplay-backend/internal/matches"
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"github.com/1shoukr/swiftplay-backend/internal/scoring"
+	"gorm.io/gorm"
+)
+
+// refreshBatchSize caps how many feeds one background run recomputes
+const refreshBatchSize = 100
+
+// feedLockClass is the first key of the Postgres advisory locks that serialize
+// replacing a user's feed; the second key is the user id
+const feedLockClass = 7_245_002
+
+// Entry is one recommended player, in feed order
+type Entry struct {
+	models.Profile `gorm:"embedded"`
+	Username       string
+	Position       int
+	Score          float64
+}
+
+// candidateRow is a discoverable profile with its owner's last activity
+type candidateRow struct {
+	models.Profile `gorm:"embedded"`
+	LastActiveAt   *time.Time
+}
+
+// Service builds and serves the per-user recommendation feeds
+type Service struct {
+	db           *gorm.DB
+	scorer       scoring.Scorer
+	config       *config.RecommendationConfig
+	passCooldown time.Duration
+}
+
+func NewService(db *gorm.DB, catalog *games.Catalog, cfg *config.RecommendationConfig, passCooldown time.Duration) *Service {
+	ladderSizes := make(map[string]int)
+	for _, game := range catalog.Games() {
+		ladderSizes[game.ID] = len(game.Ranks)
+	}
+
+	return &Service{
+		db: db,
+		scorer: scoring.Scorer{
+			Weights:          cfg.Weights,
+			LadderSizes:      ladderSizes,
+			MaxDistanceKm:    cfg.MaxDistanceKm,
+			MaxAgeGap:        cfg.MaxAgeGap,
+			ActivityHalfLife: cfg.ActivityHalfLife,
+		},
+		config:       cfg,
+		passCooldown: passCooldown,
+	}
+}
+
+// Feed returns up to limit entries of the user's feed after the given position,
+// recomputing the feed first when it is missing or older than the cache TTL.
+// Players who stopped being discoverable since the feed was computed are skipped.
+func (s *Service) Feed(ctx context.Context, userID uint, afterPosition, limit int) ([]Entry, error) {
+	db := s.db.WithContext(ctx)
+	now := time.Now()
+
+	var fresh int64
+	if err := db.Model(&models.Recommendation{}).
+		Where("user_id = ? AND computed_at > ?", userID, now.Add(-s.config.CacheTTL)).
+		Limit(1).Count(&fresh).Error; err != nil {
+		return nil, err
+	}
+	if fresh == 0 {
+		if _, err := s.Refresh(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	var entries []Entry
+	err := matches.Discoverable(db, userID, now.Add(-s.passCooldown)).
+		Select("profiles.*, users.username, recommendations.position, recommendations.score").
+		Joins("JOIN recommendations ON recommendations.candidate_id = users.user_id AND recommendations.user_id = ?", userID).
+		Where("recommendations.position > ?", afterPosition).
+		Order("recommendations.position").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+// Refresh scores the user's candidates and replaces their cached feed. It returns
+// the number of players in the new feed.
+func (s *Service) Refresh(ctx context.Context, userID uint) (int, error) {
+	db := s.db.WithContext(ctx)
+	now := time.Now()
+
+	var viewers []candidateRow
+	if err := db.Table("profiles").
+		Select("profiles.*, users.last_active_at").
+		Joins("JOIN users ON users.user_id = profiles.user_id").
+		Where("profiles.user_id = ?", userID).
+		Limit(1).Find(&viewers).Error; err != nil {
+		return 0, err
+	}
+	viewer := scoring.Player{UserID: userID}
+	if len(viewers) > 0 {
+		viewer = toPlayer(viewers[0], now)
+	}
+
+	query := matches.Discoverable(db, userID, now.Add(-s.passCooldown)).
+		Select("profiles.*, users.last_active_at")
+	if len(viewer.RankValues) > 0 {
+		// Only players sharing a game with the viewer are worth scoring. gorm treats
+		// every ? as a bind parameter, so the keys for the GIN-indexed ?| operator
+		// are inlined; they are catalog ids and quotes are escaped regardless.
+		keys := make([]string, 0, len(viewer.RankValues))
+		for game := range viewer.RankValues {
+			keys = append(keys, "'"+strings.ReplaceAll(game, "'", "''")+"'")
+		}
+		sort.Strings(keys)
+		query = query.Where(fmt.Sprintf("profiles.game_ranks ?| array[%s]", strings.Join(keys, ", ")))
+	}
+
+	var candidates []candidateRow
+	if err := query.Order("users.last_active_at DESC NULLS LAST").
+		Limit(s.config.CandidatePool).Find(&candidates).Error; err != nil {
+		return 0, err
+	}
+
+	type scored struct {
+		userID uint
+		score  scoring.Score
+	}
+	results := make([]scored, 0, len(candidates))
+	for _, candidate := range candidates {
+		results = append(results, scored{
+			userID: candidate.UserID,
+			score:  s.scorer.Score(viewer, toCandidate(candidate, now), now),
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].score.Total != results[j].score.Total {
+			return results[i].score.Total > results[j].score.Total
+		}
+		return results[i].userID < results[j].userID
+	})
+	if len(results) > s.config.FeedSize {
+		results = results[:s.config.FeedSize]
+	}
+
+	feed := make([]models.Recommendation, 0, len(results))
+	for i, result := range results {
+		c := result.score.Components
+		feed = append(feed, models.Recommendation{
+			UserID:      userID,
+			CandidateID: result.userID,
+			Position:    i + 1,
+			Score:       result.score.Total,
+			Components: map[string]float64{
+				"rank_proximity": c.RankProximity,
+				"shared_games":   c.SharedGames,
+				"distance":       c.Distance,
+				"age":            c.Age,
+				"availability":   c.Availability,
+				"activity":       c.Activity,
+			},
+			ComputedAt: now,
+		})
+	}
+
+	// Concurrent refreshes of a cold feed take turns, so the later one replaces
+	// the earlier one's rows instead of colliding with them
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", feedLockClass, int32(userID)).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&models.Recommendation{}).Error; err != nil {
+			return err
+		}
+		if len(feed) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(feed, 100).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(feed), nil
+}
+
+// RefreshActive recomputes stale feeds of recently active users so that their
+// next request is served from the cache
+func (s *Service) RefreshActive(ctx context.Context) error {
+	now := time.Now()
+
+	var userIDs []uint
+	if err := s.db.WithContext(ctx).Model(&models.User{}).
+		Where("soft_delete = ? AND last_active_at > ?", false, now.Add(-s.config.ActiveWithin)).
+		Where("NOT EXISTS (SELECT 1 FROM recommendations WHERE recommendations.user_id = users.user_id AND recommendations.computed_at > ?)",
+			now.Add(-s.config.CacheTTL)).
+		Order("last_active_at DESC").
+		Limit(refreshBatchSize).
+		Pluck("user_id", &userIDs).Error; err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		if _, err := s.Refresh(ctx, userID); err != nil {
+			return fmt.Errorf("failed to refresh recommendations for user %d: %w", userID, err)
+		}
+	}
+
+	if len(userIDs) > 0 {
+		log.Printf("Refreshed recommendations for %d users", len(userIDs))
+	}
+	return nil
+}
+
+// toCandidate is toPlayer limited to what the candidate shows everyone. Fields
+// they keep to matches or to themselves must not sway a stranger's feed, since
+// the ranking would give them away. Coordinates are as revealing as the city,
+// so they go with it.
+func toCandidate(row candidateRow, now time.Time) scoring.Player {
+	player := toPlayer(row, now)
+	if row.Privacy.City != models.VisibilityPublic {
+		player.City = ""
+		player.Latitude = nil
+		player.Longitude = nil
+	}
+	if row.Privacy.Country != models.VisibilityPublic {
+		player.Country = ""
+	}
+	if row.Privacy.Age != models.VisibilityPublic {
+		player.Age = nil
+	}
+	return player
+}
+
+// toPlayer converts a stored profile into the scorer's view of a player
+func toPlayer(row candidateRow, now time.Time) scoring.Player {
+	player := scoring.Player{
+		UserID:       row.UserID,
+		RankValues:   row.GameRankValues,
+		Latitude:     row.Latitude,
+		Longitude:    row.Longitude,
+		Availability: row.Availability,
+		LastActiveAt: row.LastActiveAt,
+	}
+	if row.City != nil {
+		player.City = *row.City
+	}
+	if row.Country != nil {
+		player.Country = *row.Country
+	}
+	if row.DateOfBirth != nil {
+		dob := *row.DateOfBirth
+		age := now.Year() - dob.Year()
+		if now.Month() < dob.Month() || (now.Month() == dob.Month() && now.Day() < dob.Day()) {
+			age--
+		}
+		player.Age = &age
+	}
+	return player
+}
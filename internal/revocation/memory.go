@@ -0,0 +1,60 @@
+package revocation
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-process Store. Revocations are lost on restart and are not
+// shared between instances, so it is only suitable for development and single-node setups.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	tokens  map[string]time.Time
+	cutoffs map[uint]time.Time
+}
+
+// NewMemoryStore creates an empty in-memory revocation store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		tokens:  make(map[string]time.Time),
+		cutoffs: make(map[uint]time.Time),
+	}
+}
+
+func (s *MemoryStore) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for id, exp := range s.tokens {
+		if now.After(exp) {
+			delete(s.tokens, id)
+		}
+	}
+
+	s.tokens[tokenID] = expiresAt
+	return nil
+}
+
+func (s *MemoryStore) RevokeUserTokens(ctx context.Context, userID uint, issuedBefore time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, ok := s.cutoffs[userID]; !ok || issuedBefore.After(current) {
+		s.cutoffs[userID] = issuedBefore
+	}
+	return nil
+}
+
+func (s *MemoryStore) IsRevoked(ctx context.Context, tokenID string, userID uint, issuedAt time.Time) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if cutoff, ok := s.cutoffs[userID]; ok && issuedAt.Before(cutoff) {
+		return true, nil
+	}
+
+	_, revoked := s.tokens[tokenID]
+	return revoked, nil
+}
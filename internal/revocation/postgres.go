@@ -0,0 +1,62 @@
+package revocation
+
+import (
+	"context"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/database"
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresStore is a Store backed by the revoked_tokens and token_cutoffs tables,
+// shared by every instance of the API
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+// NewPostgresStore creates a revocation store on top of the application database
+func NewPostgresStore(db *database.Database) *PostgresStore {
+	return &PostgresStore{db: db.GetDB()}
+}
+
+func (s *PostgresStore) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	db := s.db.WithContext(ctx)
+
+	// Expired entries can never match a valid token again
+	if err := db.Where("expires_at < ?", time.Now()).Delete(&models.RevokedToken{}).Error; err != nil {
+		return err
+	}
+
+	return db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.RevokedToken{TokenID: tokenID, ExpiresAt: expiresAt}).Error
+}
+
+func (s *PostgresStore) RevokeUserTokens(ctx context.Context, userID uint, issuedBefore time.Time) error {
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "revoked_before"}, Value: gorm.Expr("GREATEST(token_cutoffs.revoked_before, EXCLUDED.revoked_before)")},
+			{Column: clause.Column{Name: "updated_at"}, Value: gorm.Expr("EXCLUDED.updated_at")},
+		},
+	}).Create(&models.TokenCutoff{UserID: userID, RevokedBefore: issuedBefore}).Error
+}
+
+func (s *PostgresStore) IsRevoked(ctx context.Context, tokenID string, userID uint, issuedAt time.Time) (bool, error) {
+	db := s.db.WithContext(ctx)
+
+	var cutoffs []models.TokenCutoff
+	if err := db.Where("user_id = ?", userID).Limit(1).Find(&cutoffs).Error; err != nil {
+		return false, err
+	}
+	if len(cutoffs) > 0 && issuedAt.Before(cutoffs[0].RevokedBefore) {
+		return true, nil
+	}
+
+	var count int64
+	if err := db.Model(&models.RevokedToken{}).Where("token_id = ?", tokenID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,33 @@
+package revocation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/database"
+)
+
+// Store records access tokens that must be rejected before they expire. Tokens
+// can be revoked individually by their jti, or all at once for a user by
+// recording a cutoff: any token issued before it is considered revoked.
+type Store interface {
+	// RevokeToken revokes a single token until its expiry
+	RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+	// RevokeUserTokens revokes every token issued to the user before the given time
+	RevokeUserTokens(ctx context.Context, userID uint, issuedBefore time.Time) error
+	// IsRevoked reports whether the token with the given identity has been revoked
+	IsRevoked(ctx context.Context, tokenID string, userID uint, issuedAt time.Time) (bool, error)
+}
+
+// NewStore creates the store implementation selected by kind ("memory" or "postgres")
+func NewStore(kind string, db *database.Database) (Store, error) {
+	switch kind {
+	case "memory":
+		return NewMemoryStore(), nil
+	case "postgres":
+		return NewPostgresStore(db), nil
+	default:
+		return nil, fmt.Errorf("unknown revocation store: %s", kind)
+	}
+}
@@ -0,0 +1,27 @@
+package scoring
+
+var (
+	availabilityDays    = []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+	availabilityPeriods = []string{"morning", "afternoon", "evening", "night"}
+)
+
+// AvailabilitySlots lists every weekly play-time slot, such as "sat_evening"
+func AvailabilitySlots() []string {
+	slots := make([]string, 0, len(availabilityDays)*len(availabilityPeriods))
+	for _, day := range availabilityDays {
+		for _, period := range availabilityPeriods {
+			slots = append(slots, day+"_"+period)
+		}
+	}
+	return slots
+}
+
+// IsAvailabilitySlot reports whether slot is one of AvailabilitySlots
+func IsAvailabilitySlot(slot string) bool {
+	for _, s := range AvailabilitySlots() {
+		if s == slot {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+// Package scoring rates how compatible two players are. It has no database or
+// HTTP dependencies so the scorer can be exercised with plain table tests.
+package scoring
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// Weights sets how much each component contributes to the total score.
+// Weights are relative; a zero weight switches the component off.
+type Weights struct {
+	RankProximity float64
+	SharedGames   float64
+	Distance      float64
+	Age           float64
+	Availability  float64
+	Activity      float64
+}
+
+// DefaultWeights favour players of a similar skill who play the same games
+func DefaultWeights() Weights {
+	return Weights{
+		RankProximity: 3,
+		SharedGames:   2,
+		Distance:      2,
+		Age:           1,
+		Availability:  2,
+		Activity:      1,
+	}
+}
+
+// Player is everything the scorer knows about one side of a pairing
+type Player struct {
+	UserID uint
+	// RankValues maps catalog game ids to the player's numeric rank
+	RankValues   map[string]int
+	Latitude     *float64
+	Longitude    *float64
+	City         string
+	Country      string
+	Age          *int
+	Availability []string
+	LastActiveAt *time.Time
+}
+
+// Components are the individual scores, each between 0 and 1
+type Components struct {
+	RankProximity float64 `json:"rank_proximity"`
+	SharedGames   float64 `json:"shared_games"`
+	Distance      float64 `json:"distance"`
+	Age           float64 `json:"age"`
+	Availability  float64 `json:"availability"`
+	Activity      float64 `json:"activity"`
+}
+
+// Score is a candidate's total compatibility, between 0 and 1, and its parts
+type Score struct {
+	Total      float64    `json:"total"`
+	Components Components `json:"components"`
+}
+
+// Scorer rates candidates against a viewer
+type Scorer struct {
+	Weights Weights
+	// LadderSizes maps catalog game ids to the number of ranks on their ladder,
+	// used to turn a rank gap into a fraction of the whole ladder
+	LadderSizes map[string]int
+	// MaxDistanceKm is the distance at which the distance score reaches zero
+	MaxDistanceKm float64
+	// MaxAgeGap is the age difference in years at which the age score reaches zero
+	MaxAgeGap int
+	// ActivityHalfLife is how long after a player's last activity their activity score halves
+	ActivityHalfLife time.Duration
+}
+
+// Score rates how good a match the candidate is for the viewer at time now
+func (s Scorer) Score(viewer, candidate Player, now time.Time) Score {
+	components := Components{
+		RankProximity: s.rankProximity(viewer, candidate),
+		SharedGames:   sharedGames(viewer, candidate),
+		Distance:      s.distance(viewer, candidate),
+		Age:           s.age(viewer, candidate),
+		Availability:  availabilityOverlap(viewer.Availability, candidate.Availability),
+		Activity:      s.activity(candidate, now),
+	}
+
+	w := s.Weights
+	weighted := w.RankProximity*components.RankProximity +
+		w.SharedGames*components.SharedGames +
+		w.Distance*components.Distance +
+		w.Age*components.Age +
+		w.Availability*components.Availability +
+		w.Activity*components.Activity
+	totalWeight := w.RankProximity + w.SharedGames + w.Distance + w.Age + w.Availability + w.Activity
+
+	score := Score{Components: components}
+	if totalWeight > 0 {
+		score.Total = weighted / totalWeight
+	}
+	return score
+}
+
+// rankProximity averages, over the games both players rank in, how close their
+// ranks are as a fraction of that game's ladder
+func (s Scorer) rankProximity(viewer, candidate Player) float64 {
+	var sum float64
+	shared := 0
+	for game, viewerRank := range viewer.RankValues {
+		candidateRank, ok := candidate.RankValues[game]
+		if !ok {
+			continue
+		}
+		shared++
+
+		span := s.LadderSizes[game] - 1
+		if span <= 0 {
+			if viewerRank == candidateRank {
+				sum++
+			}
+			continue
+		}
+		gap := math.Abs(float64(viewerRank - candidateRank))
+		sum += clamp(1 - gap/float64(span))
+	}
+
+	if shared == 0 {
+		return 0
+	}
+	return sum / float64(shared)
+}
+
+// sharedGames is the fraction of the viewer's games the candidate also plays
+func sharedGames(viewer, candidate Player) float64 {
+	if len(viewer.RankValues) == 0 {
+		return 0
+	}
+	shared := 0
+	for game := range viewer.RankValues {
+		if _, ok := candidate.RankValues[game]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(viewer.RankValues))
+}
+
+// distance falls off linearly with the great-circle distance between the
+// players. Without coordinates it falls back to comparing city and country.
+func (s Scorer) distance(viewer, candidate Player) float64 {
+	if hasCoordinates(viewer) && hasCoordinates(candidate) && s.MaxDistanceKm > 0 {
+		km := haversineKm(*viewer.Latitude, *viewer.Longitude, *candidate.Latitude, *candidate.Longitude)
+		return clamp(1 - km/s.MaxDistanceKm)
+	}
+
+	switch {
+	case viewer.Country == "" || !strings.EqualFold(viewer.Country, candidate.Country):
+		return 0
+	case viewer.City != "" && strings.EqualFold(viewer.City, candidate.City):
+		return 0.8
+	default:
+		return 0.4
+	}
+}
+
+// age falls off linearly with the age gap between the players
+func (s Scorer) age(viewer, candidate Player) float64 {
+	if viewer.Age == nil || candidate.Age == nil || s.MaxAgeGap <= 0 {
+		return 0
+	}
+	gap := math.Abs(float64(*viewer.Age - *candidate.Age))
+	return clamp(1 - gap/float64(s.MaxAgeGap))
+}
+
+// activity halves for every ActivityHalfLife since the candidate was last active
+func (s Scorer) activity(candidate Player, now time.Time) float64 {
+	if candidate.LastActiveAt == nil || s.ActivityHalfLife <= 0 {
+		return 0
+	}
+	idle := now.Sub(*candidate.LastActiveAt)
+	if idle <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, float64(idle)/float64(s.ActivityHalfLife))
+}
+
+// availabilityOverlap is the Jaccard similarity of the two sets of play-time slots
+func availabilityOverlap(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	inA := make(map[string]bool, len(a))
+	for _, slot := range a {
+		inA[slot] = true
+	}
+
+	union := len(inA)
+	both := 0
+	seen := make(map[string]bool, len(b))
+	for _, slot := range b {
+		if seen[slot] {
+			continue
+		}
+		seen[slot] = true
+		if inA[slot] {
+			both++
+		} else {
+			union++
+		}
+	}
+	return float64(both) / float64(union)
+}
+
+func hasCoordinates(p Player) bool {
+	return p.Latitude != nil && p.Longitude != nil
+}
+
+const earthRadiusKm = 6371.0
+
+// haversineKm is the great-circle distance between two points in kilometres
+func haversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -0,0 +1,355 @@
+package scoring
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+const tolerance = 1e-9
+
+func floatPtr(v float64) *float64 { return &v }
+
+func intPtr(v int) *int { return &v }
+
+func timePtr(t time.Time) *time.Time { return &t }
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < tolerance
+}
+
+func testScorer() Scorer {
+	return Scorer{
+		Weights:          DefaultWeights(),
+		LadderSizes:      map[string]int{"valorant": 11, "chess": 1},
+		MaxDistanceKm:    100,
+		MaxAgeGap:        10,
+		ActivityHalfLife: 24 * time.Hour,
+	}
+}
+
+func TestRankProximity(t *testing.T) {
+	tests := []struct {
+		name      string
+		viewer    map[string]int
+		candidate map[string]int
+		want      float64
+	}{
+		{"same rank", map[string]int{"valorant": 5}, map[string]int{"valorant": 5}, 1},
+		{"half the ladder apart", map[string]int{"valorant": 0}, map[string]int{"valorant": 5}, 0.5},
+		{"opposite ends of the ladder", map[string]int{"valorant": 0}, map[string]int{"valorant": 10}, 0},
+		{"averaged over shared games", map[string]int{"valorant": 0, "chess": 0}, map[string]int{"valorant": 10, "chess": 0}, 0.5},
+		{"single rank ladder, different ranks", map[string]int{"chess": 0}, map[string]int{"chess": 1}, 0},
+		{"unknown ladder, same rank", map[string]int{"lol": 3}, map[string]int{"lol": 3}, 1},
+		{"no shared games", map[string]int{"valorant": 5}, map[string]int{"lol": 5}, 0},
+		{"viewer has no games", nil, map[string]int{"valorant": 5}, 0},
+	}
+
+	s := testScorer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.rankProximity(Player{RankValues: tt.viewer}, Player{RankValues: tt.candidate})
+			if !approxEqual(got, tt.want) {
+				t.Errorf("rankProximity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSharedGames(t *testing.T) {
+	tests := []struct {
+		name      string
+		viewer    map[string]int
+		candidate map[string]int
+		want      float64
+	}{
+		{"all games shared", map[string]int{"valorant": 1, "lol": 1}, map[string]int{"valorant": 3, "lol": 7}, 1},
+		{"half the games shared", map[string]int{"valorant": 1, "lol": 1}, map[string]int{"valorant": 3}, 0.5},
+		{"candidate plays more games", map[string]int{"valorant": 1}, map[string]int{"valorant": 3, "lol": 7}, 1},
+		{"no shared games", map[string]int{"valorant": 1}, map[string]int{"lol": 1}, 0},
+		{"viewer has no games", nil, map[string]int{"lol": 1}, 0},
+		{"candidate has no games", map[string]int{"valorant": 1}, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sharedGames(Player{RankValues: tt.viewer}, Player{RankValues: tt.candidate})
+			if !approxEqual(got, tt.want) {
+				t.Errorf("sharedGames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	// One degree of longitude along the equator is about 111.19 km
+	oneDegreeKm := haversineKm(0, 0, 0, 1)
+
+	tests := []struct {
+		name          string
+		maxDistanceKm float64
+		viewer        Player
+		candidate     Player
+		want          float64
+	}{
+		{
+			name:          "same coordinates",
+			maxDistanceKm: 100,
+			viewer:        Player{Latitude: floatPtr(48.85), Longitude: floatPtr(2.35)},
+			candidate:     Player{Latitude: floatPtr(48.85), Longitude: floatPtr(2.35)},
+			want:          1,
+		},
+		{
+			name:          "half the maximum distance",
+			maxDistanceKm: 2 * oneDegreeKm,
+			viewer:        Player{Latitude: floatPtr(0), Longitude: floatPtr(0)},
+			candidate:     Player{Latitude: floatPtr(0), Longitude: floatPtr(1)},
+			want:          0.5,
+		},
+		{
+			name:          "beyond the maximum distance",
+			maxDistanceKm: 100,
+			viewer:        Player{Latitude: floatPtr(0), Longitude: floatPtr(0)},
+			candidate:     Player{Latitude: floatPtr(0), Longitude: floatPtr(10)},
+			want:          0,
+		},
+		{
+			name:          "coordinates take precedence over city",
+			maxDistanceKm: 100,
+			viewer:        Player{Latitude: floatPtr(0), Longitude: floatPtr(0), City: "Paris", Country: "FR"},
+			candidate:     Player{Latitude: floatPtr(0), Longitude: floatPtr(10), City: "Paris", Country: "FR"},
+			want:          0,
+		},
+		{
+			name:          "candidate without coordinates falls back to city",
+			maxDistanceKm: 100,
+			viewer:        Player{Latitude: floatPtr(0), Longitude: floatPtr(0), City: "Paris", Country: "FR"},
+			candidate:     Player{City: "paris", Country: "fr"},
+			want:          0.8,
+		},
+		{
+			name:          "no maximum distance falls back to city",
+			maxDistanceKm: 0,
+			viewer:        Player{Latitude: floatPtr(0), Longitude: floatPtr(0), City: "Paris", Country: "FR"},
+			candidate:     Player{Latitude: floatPtr(0), Longitude: floatPtr(0), City: "Paris", Country: "FR"},
+			want:          0.8,
+		},
+		{
+			name:      "same country, different city",
+			viewer:    Player{City: "Paris", Country: "FR"},
+			candidate: Player{City: "Lyon", Country: "FR"},
+			want:      0.4,
+		},
+		{
+			name:      "different country",
+			viewer:    Player{City: "Paris", Country: "FR"},
+			candidate: Player{City: "Paris", Country: "US"},
+			want:      0,
+		},
+		{
+			name:      "viewer location missing",
+			viewer:    Player{},
+			candidate: Player{City: "Paris", Country: "FR"},
+			want:      0,
+		},
+		{
+			name:      "candidate location missing",
+			viewer:    Player{City: "Paris", Country: "FR"},
+			candidate: Player{},
+			want:      0,
+		},
+		{
+			name:      "both locations missing",
+			viewer:    Player{},
+			candidate: Player{},
+			want:      0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Scorer{MaxDistanceKm: tt.maxDistanceKm}
+			got := s.distance(tt.viewer, tt.candidate)
+			if !approxEqual(got, tt.want) {
+				t.Errorf("distance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAge(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxAgeGap int
+		viewer    *int
+		candidate *int
+		want      float64
+	}{
+		{"same age", 10, intPtr(25), intPtr(25), 1},
+		{"half the maximum gap", 10, intPtr(25), intPtr(30), 0.5},
+		{"gap in either direction", 10, intPtr(30), intPtr(25), 0.5},
+		{"beyond the maximum gap", 10, intPtr(20), intPtr(40), 0},
+		{"viewer date of birth missing", 10, nil, intPtr(25), 0},
+		{"candidate date of birth missing", 10, intPtr(25), nil, 0},
+		{"no maximum gap", 0, intPtr(25), intPtr(25), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Scorer{MaxAgeGap: tt.maxAgeGap}
+			got := s.age(Player{Age: tt.viewer}, Player{Age: tt.candidate})
+			if !approxEqual(got, tt.want) {
+				t.Errorf("age() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAvailabilityOverlap(t *testing.T) {
+	tests := []struct {
+		name string
+		a    []string
+		b    []string
+		want float64
+	}{
+		{"identical slots", []string{"sat_evening", "sun_evening"}, []string{"sun_evening", "sat_evening"}, 1},
+		{"partial overlap", []string{"sat_evening", "sun_evening"}, []string{"sat_evening", "fri_night"}, 1.0 / 3},
+		{"no overlap", []string{"sat_evening"}, []string{"mon_morning"}, 0},
+		{"duplicate slots count once", []string{"sat_evening"}, []string{"sat_evening", "sat_evening"}, 1},
+		{"first side empty", nil, []string{"sat_evening"}, 0},
+		{"second side empty", []string{"sat_evening"}, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := availabilityOverlap(tt.a, tt.b)
+			if !approxEqual(got, tt.want) {
+				t.Errorf("availabilityOverlap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActivity(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		halfLife     time.Duration
+		lastActiveAt *time.Time
+		want         float64
+	}{
+		{"active now", 24 * time.Hour, timePtr(now), 1},
+		{"active in the future", 24 * time.Hour, timePtr(now.Add(time.Hour)), 1},
+		{"one half-life ago", 24 * time.Hour, timePtr(now.Add(-24 * time.Hour)), 0.5},
+		{"two half-lives ago", 24 * time.Hour, timePtr(now.Add(-48 * time.Hour)), 0.25},
+		{"never active", 24 * time.Hour, nil, 0},
+		{"no half-life", 0, timePtr(now), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Scorer{ActivityHalfLife: tt.halfLife}
+			got := s.activity(Player{LastActiveAt: tt.lastActiveAt}, now)
+			if !approxEqual(got, tt.want) {
+				t.Errorf("activity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreWeights(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// Components: rank_proximity 0.5, shared_games 0.5, distance 0.4, age 0.5,
+	// availability 1, activity 0.5
+	viewer := Player{
+		RankValues:   map[string]int{"valorant": 0, "lol": 3},
+		City:         "Paris",
+		Country:      "FR",
+		Age:          intPtr(25),
+		Availability: []string{"sat_evening"},
+	}
+	candidate := Player{
+		RankValues:   map[string]int{"valorant": 5},
+		City:         "Lyon",
+		Country:      "FR",
+		Age:          intPtr(30),
+		Availability: []string{"sat_evening"},
+		LastActiveAt: timePtr(now.Add(-24 * time.Hour)),
+	}
+	wantComponents := Components{
+		RankProximity: 0.5,
+		SharedGames:   0.5,
+		Distance:      0.4,
+		Age:           0.5,
+		Availability:  1,
+		Activity:      0.5,
+	}
+
+	tests := []struct {
+		name    string
+		weights Weights
+		want    float64
+	}{
+		{
+			name:    "default weights",
+			weights: DefaultWeights(),
+			// (3*0.5 + 2*0.5 + 2*0.4 + 1*0.5 + 2*1 + 1*0.5) / 11
+			want: 6.3 / 11,
+		},
+		{
+			name:    "equal weights",
+			weights: Weights{RankProximity: 1, SharedGames: 1, Distance: 1, Age: 1, Availability: 1, Activity: 1},
+			want:    3.4 / 6,
+		},
+		{
+			name:    "weights are relative",
+			weights: Weights{RankProximity: 5, SharedGames: 5, Distance: 5, Age: 5, Availability: 5, Activity: 5},
+			want:    3.4 / 6,
+		},
+		{
+			name:    "single component",
+			weights: Weights{Availability: 1},
+			want:    1,
+		},
+		{
+			name:    "zero weight switches a component off",
+			weights: Weights{Distance: 1, Availability: 1},
+			want:    0.7,
+		},
+		{
+			name:    "all weights zero",
+			weights: Weights{},
+			want:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := testScorer()
+			s.Weights = tt.weights
+			got := s.Score(viewer, candidate, now)
+			if !approxEqual(got.Total, tt.want) {
+				t.Errorf("Score().Total = %v, want %v", got.Total, tt.want)
+			}
+			if got.Components != wantComponents {
+				t.Errorf("Score().Components = %+v, want %+v", got.Components, wantComponents)
+			}
+		})
+	}
+}
+
+func TestScoreNoSharedGames(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	viewer := Player{RankValues: map[string]int{"valorant": 5}}
+	candidate := Player{RankValues: map[string]int{"lol": 5}}
+
+	got := testScorer().Score(viewer, candidate, now)
+	if got.Total != 0 {
+		t.Errorf("Score().Total = %v, want 0", got.Total)
+	}
+	if got.Components != (Components{}) {
+		t.Errorf("Score().Components = %+v, want all zero", got.Components)
+	}
+}
@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"github.com/1shoukr/swiftplay-backend/internal/handlers"
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupAdminRoutes(api *gin.RouterGroup, deps *Dependencies) {
+	adminHandler := handlers.NewAdminHandler(deps.DB, deps.JWTService, deps.UserStates, deps.RBAC, deps.Hub, deps.Stream, deps.Audit)
+	reportHandler := handlers.NewReportHandler(deps.DB)
+	auditHandler := handlers.NewAuditHandler(deps.DB)
+	adminJWT := deps.JWTService.ForAudience(deps.Config.JWT.AdminAudience)
+
+	admin := api.Group("/admin")
+	admin.Use(middleware.AuditRequests(deps.Audit))
+
+	users := admin.Group("/users")
+	{
+		users.GET("", middleware.RequirePermission(adminJWT, deps.RBAC, rbac.PermUsersRead), adminHandler.ListUsers)
+		users.GET("/:id", middleware.RequirePermission(adminJWT, deps.RBAC, rbac.PermUsersRead), adminHandler.GetUser)
+		users.GET("/:id/reports", middleware.RequirePermission(adminJWT, deps.RBAC, rbac.PermUsersRead, rbac.PermReportsReview), adminHandler.ListUserReports)
+		users.GET("/:id/messages", middleware.RequirePermission(adminJWT, deps.RBAC, rbac.PermUsersRead, rbac.PermMessagesModerate), adminHandler.ListUserMessages)
+		users.POST("/:id/suspend", middleware.RequirePermission(adminJWT, deps.RBAC, rbac.PermUsersSuspend), adminHandler.SuspendUser)
+		users.POST("/:id/ban", middleware.RequirePermission(adminJWT, deps.RBAC, rbac.PermUsersBan), adminHandler.BanUser)
+		users.POST("/:id/reinstate", middleware.RequirePermission(adminJWT, deps.RBAC, rbac.PermUsersSuspend), adminHandler.ReinstateUser)
+		users.POST("/:id/restore", middleware.RequirePermission(adminJWT, deps.RBAC, rbac.PermUsersRestore), adminHandler.RestoreUser)
+		users.PATCH("/:id/role", middleware.RequirePermission(adminJWT, deps.RBAC, rbac.PermRolesAssign), adminHandler.ChangeRole)
+	}
+
+	reports := admin.Group("/reports")
+	reports.Use(middleware.RequirePermission(adminJWT, deps.RBAC, rbac.PermReportsReview))
+	{
+		reports.GET("", reportHandler.ListReports)
+		reports.GET("/:id", reportHandler.GetReport)
+		reports.PATCH("/:id", reportHandler.ReviewReport)
+	}
+
+	admin.GET("/audit", middleware.RequirePermission(adminJWT, deps.RBAC, rbac.PermAuditRead), auditHandler.ListAuditRecords)
+}
@@ -1,17 +1,56 @@
 package routes
 
 import (
-	"github.com/1shoukr/swiftplay-backend/internal/database"
 	"github.com/1shoukr/swiftplay-backend/internal/handlers"
-	"github.com/1shoukr/swiftplay-backend/internal/jwt"
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/ratelimit"
+	"github.com/1shoukr/swiftplay-backend/internal/rbac"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupAuthRoutes(api *gin.RouterGroup, db *database.Database, jwtService *jwt.JWTService) {
-	authHandler := handlers.NewAuthHandler(db)
+func SetupAuthRoutes(api *gin.RouterGroup, deps *Dependencies) {
+	loginLockout := ratelimit.NewLockout(deps.RateLimits, ratelimit.LockoutPolicy{
+		Threshold: deps.Config.RateLimit.LockoutThreshold,
+		BaseDelay: deps.Config.RateLimit.LockoutBaseDelay,
+		MaxDelay:  deps.Config.RateLimit.LockoutMaxDelay,
+		Window:    deps.Config.RateLimit.LockoutWindow,
+	})
+	authHandler := handlers.NewAuthHandler(deps.DB, deps.JWTService, deps.Mailer, deps.RBAC, deps.Config.Auth, deps.Audit, loginLockout)
+	requireAccount := middleware.RequirePermission(deps.JWTService, deps.RBAC, rbac.PermAccountManage)
+
+	limitAuth := authLimiter(deps).Middleware()
+	limitRefresh := refreshLimiter(deps).Middleware()
 
 	auth := api.Group("/auth")
 	{
-		auth.GET("/login", authHandler.Login)
+		auth.POST("/login", limitAuth, authHandler.Login)
+		auth.POST("/refresh", limitRefresh, authHandler.Refresh)
+		auth.POST("/logout", middleware.RequireAuth(deps.JWTService), authHandler.Logout)
+		auth.POST("/logout-all", middleware.RequireAuth(deps.JWTService), authHandler.LogoutAll)
+		auth.POST("/verify-email", authHandler.VerifyEmail)
+		auth.POST("/resend-verification", requireAccount, authHandler.ResendVerification)
+		auth.POST("/forgot-password", limitAuth, authHandler.ForgotPassword)
+		auth.POST("/reset-password", limitAuth, authHandler.ResetPassword)
+		auth.POST("/change-password", requireAccount, authHandler.ChangePassword)
+
+		mfa := auth.Group("/mfa")
+		{
+			mfa.POST("/verify", limitAuth, authHandler.VerifyMFA)
+			mfa.POST("/enroll", limitAuth, middleware.RequireMFASetup(deps.JWTService), authHandler.EnrollMFA)
+			mfa.POST("/confirm", limitAuth, middleware.RequireMFASetup(deps.JWTService), authHandler.ConfirmMFA)
+			mfa.POST("/disable", requireAccount, authHandler.DisableMFA)
+		}
 	}
 }
+
+// authLimiter limits sign-in, MFA, registration and password reset requests
+// per client IP
+func authLimiter(deps *Dependencies) *ratelimit.Limiter {
+	return ratelimit.NewLimiter(deps.RateLimits, "auth", ratelimit.PerMinute(deps.Config.RateLimit.Auth), ratelimit.ByIP)
+}
+
+// refreshLimiter limits token refreshes per client IP. Clients refresh routinely,
+// so they get a looser limit of their own rather than sharing the auth bucket.
+func refreshLimiter(deps *Dependencies) *ratelimit.Limiter {
+	return ratelimit.NewLimiter(deps.RateLimits, "refresh", ratelimit.PerMinute(deps.Config.RateLimit.Refresh), ratelimit.ByIP)
+}
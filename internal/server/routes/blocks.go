@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/1shoukr/swiftplay-backend/internal/handlers"
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupBlockRoutes(api *gin.RouterGroup, deps *Dependencies) {
+	blockHandler := handlers.NewBlockHandler(deps.DB, deps.Matches)
+	reportHandler := handlers.NewReportHandler(deps.DB)
+	mobileJWT := deps.JWTService.ForAudience(deps.Config.JWT.MobileAudience)
+
+	blocks := api.Group("/blocks")
+	blocks.Use(middleware.RequirePermission(mobileJWT, deps.RBAC, rbac.PermMatchesUse))
+	{
+		blocks.GET("", blockHandler.ListBlocks)
+		blocks.POST("/:username", blockHandler.Block)
+		blocks.DELETE("/:username", blockHandler.Unblock)
+	}
+
+	api.POST("/reports", middleware.RequirePermission(mobileJWT, deps.RBAC, rbac.PermMatchesUse), reportHandler.CreateReport)
+}
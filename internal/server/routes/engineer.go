@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/1shoukr/swiftplay-backend/internal/handlers"
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupEngineerRoutes(api *gin.RouterGroup, deps *Dependencies) {
+	rbacHandler := handlers.NewRBACHandler(deps.RBAC)
+	adminJWT := deps.JWTService.ForAudience(deps.Config.JWT.AdminAudience)
+
+	engineer := api.Group("/engineer")
+	engineer.Use(middleware.AuditRequests(deps.Audit))
+	{
+		engineer.GET("/permissions", middleware.RequirePermission(adminJWT, deps.RBAC, rbac.PermRBACInspect), rbacHandler.PermissionMatrix)
+	}
+}
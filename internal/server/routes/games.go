@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/1shoukr/swiftplay-backend/internal/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupGameRoutes(api *gin.RouterGroup, deps *Dependencies) {
+	gameHandler := handlers.NewGameHandler(deps.Games)
+
+	gamesGroup := api.Group("/games")
+	{
+		gamesGroup.GET("", gameHandler.ListGames)
+		gamesGroup.GET("/:game", gameHandler.GetGame)
+	}
+}
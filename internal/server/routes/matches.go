@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/1shoukr/swiftplay-backend/internal/handlers"
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupMatchRoutes(api *gin.RouterGroup, deps *Dependencies) {
+	matchHandler := handlers.NewMatchHandler(deps.DB, deps.Matches)
+	mobileJWT := deps.JWTService.ForAudience(deps.Config.JWT.MobileAudience)
+
+	swipes := api.Group("/swipes")
+	swipes.Use(middleware.RequirePermission(mobileJWT, deps.RBAC, rbac.PermMatchesUse))
+	{
+		swipes.POST("/:username/like", matchHandler.Like)
+		swipes.POST("/:username/super-like", matchHandler.SuperLike)
+		swipes.POST("/:username/pass", matchHandler.Pass)
+	}
+
+	matchesGroup := api.Group("/matches")
+	matchesGroup.Use(middleware.RequirePermission(mobileJWT, deps.RBAC, rbac.PermMatchesUse))
+	{
+		matchesGroup.GET("", matchHandler.ListMatches)
+		matchesGroup.POST("/:id/unmatch", matchHandler.Unmatch)
+	}
+}
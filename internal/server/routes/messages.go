@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/1shoukr/swiftplay-backend/internal/handlers"
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/ratelimit"
+	"github.com/1shoukr/swiftplay-backend/internal/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupMessageRoutes(api *gin.RouterGroup, deps *Dependencies) {
+	messageHandler := handlers.NewMessageHandler(deps.DB, deps.Chat)
+	mobileJWT := deps.JWTService.ForAudience(deps.Config.JWT.MobileAudience)
+
+	messages := api.Group("/matches/:id/messages")
+	messages.Use(middleware.RequirePermission(mobileJWT, deps.RBAC, rbac.PermMessagesSend))
+	{
+		messages.GET("", messageHandler.ListMessages)
+		messages.POST("", messageLimiter(deps).Middleware(), messageHandler.SendMessage)
+		messages.PATCH("", messageHandler.MarkMessagesRead)
+	}
+}
+
+// messageLimiter limits how fast a player can send chat messages, over HTTP and
+// the WebSocket alike
+func messageLimiter(deps *Dependencies) *ratelimit.Limiter {
+	return ratelimit.NewLimiter(deps.RateLimits, "messages", ratelimit.PerMinute(deps.Config.RateLimit.Messages), ratelimit.ByUser)
+}
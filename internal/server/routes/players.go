@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/1shoukr/swiftplay-backend/internal/handlers"
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupPlayerRoutes(api *gin.RouterGroup, deps *Dependencies) {
+	playerHandler := handlers.NewPlayerHandler(deps.DB, deps.Games, deps.Config.Matchmaking)
+	mobileJWT := deps.JWTService.ForAudience(deps.Config.JWT.MobileAudience)
+
+	players := api.Group("/players")
+	players.Use(middleware.RequirePermission(mobileJWT, deps.RBAC, rbac.PermPlayersRead))
+	{
+		players.GET("/search", playerHandler.SearchPlayers)
+		players.GET("/:username", playerHandler.GetPlayer)
+	}
+}
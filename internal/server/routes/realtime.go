@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/1shoukr/swiftplay-backend/internal/handlers"
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRealtimeRoutes(api *gin.RouterGroup, deps *Dependencies) {
+	realtimeHandler := handlers.NewRealtimeHandler(deps.Hub, deps.Chat, messageLimiter(deps))
+	streamHandler := handlers.NewStreamHandler(deps.Stream, deps.Config.Realtime.StreamKeepAlive)
+	mobileJWT := deps.JWTService.ForAudience(deps.Config.JWT.MobileAudience)
+
+	api.GET("/ws", middleware.RequirePermission(mobileJWT, deps.RBAC, rbac.PermMessagesSend), realtimeHandler.Connect)
+	api.GET("/stream", middleware.RequirePermission(mobileJWT, deps.RBAC, rbac.PermMatchesUse), streamHandler.Stream)
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/1shoukr/swiftplay-backend/internal/handlers"
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRecommendationRoutes(api *gin.RouterGroup, deps *Dependencies) {
+	recommendationHandler := handlers.NewRecommendationHandler(deps.Recommendations)
+	mobileJWT := deps.JWTService.ForAudience(deps.Config.JWT.MobileAudience)
+
+	api.GET("/recommendations", middleware.RequirePermission(mobileJWT, deps.RBAC, rbac.PermPlayersRead), recommendationHandler.GetFeed)
+}
@@ -3,28 +3,97 @@ package routes
 import (
 	"net/http"
 
+	"github.com/1shoukr/swiftplay-backend/internal/audit"
+	"github.com/1shoukr/swiftplay-backend/internal/chat"
+	"github.com/1shoukr/swiftplay-backend/internal/config"
 	"github.com/1shoukr/swiftplay-backend/internal/database"
+	"github.com/1shoukr/swiftplay-backend/internal/games"
 	"github.com/1shoukr/swiftplay-backend/internal/jwt"
+	"github.com/1shoukr/swiftplay-backend/internal/mailer"
+	"github.com/1shoukr/swiftplay-backend/internal/matches"
+	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/ratelimit"
+	"github.com/1shoukr/swiftplay-backend/internal/rbac"
+	"github.com/1shoukr/swiftplay-backend/internal/realtime"
+	"github.com/1shoukr/swiftplay-backend/internal/recommendations"
+	"github.com/1shoukr/swiftplay-backend/internal/userstate"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRoutes(r *gin.Engine, db *database.Database, jwtService *jwt.JWTService) {
+// Dependencies bundles the shared services the route groups are built from
+type Dependencies struct {
+	DB              *database.Database
+	JWTService      *jwt.JWTService
+	Mailer          mailer.Mailer
+	RBAC            *rbac.Policy
+	UserStates      *userstate.Cache
+	Games           *games.Catalog
+	Matches         *matches.Service
+	Recommendations *recommendations.Service
+	Hub             *realtime.Hub
+	Stream          *realtime.Stream
+	Chat            *chat.Service
+	Audit           *audit.Logger
+	RateLimits      ratelimit.Store
+	Config          *config.ServerConfig
+}
+
+func SetupRoutes(r *gin.Engine, deps *Dependencies) {
 	// Logger middleware (Gin has built-in logger)
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
 
+	// Check roles and account status against the database instead of the token
+	if deps.Config.Auth.LiveUserLookup {
+		r.Use(middleware.WithUserStates(deps.UserStates))
+	}
+
 	// Health check endpoint
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "OK"})
 	})
 
+	// Public signing keys so other services can verify access tokens
+	r.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, deps.JWTService.JWKS())
+	})
+
 	// API route group
 	api := r.Group("/api")
+	api.Use(ratelimit.NewLimiter(deps.RateLimits, "api", ratelimit.PerMinute(deps.Config.RateLimit.Default), ratelimit.ByIP).Middleware())
 	{
 		// Mount auth routes under /api/auth
-		SetupAuthRoutes(api, db, jwtService)
+		SetupAuthRoutes(api, deps)
 
 		// Mount user routes under /api/users
-		SetupUserRoutes(api, db, jwtService)
+		SetupUserRoutes(api, deps)
+
+		// Mount player lookup under /api/players
+		SetupPlayerRoutes(api, deps)
+
+		// Mount swipes and matches under /api/swipes and /api/matches
+		SetupMatchRoutes(api, deps)
+
+		// Mount chat under /api/matches/:id/messages
+		SetupMessageRoutes(api, deps)
+
+		// Mount blocking and reporting under /api/blocks and /api/reports
+		SetupBlockRoutes(api, deps)
+
+		// Mount the chat WebSocket at /api/ws and the event stream at /api/stream
+		SetupRealtimeRoutes(api, deps)
+
+		// Mount the recommendation feed at /api/recommendations
+		SetupRecommendationRoutes(api, deps)
+
+		// Mount the game catalog under /api/games
+		SetupGameRoutes(api, deps)
+
+		// Mount the admin console under /api/admin
+		SetupAdminRoutes(api, deps)
+
+		// Mount engineer tooling under /api/engineer
+		SetupEngineerRoutes(api, deps)
 	}
 }
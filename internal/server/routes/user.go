@@ -3,24 +3,25 @@ package routes
 import (
 	"net/http"
 
-	"github.com/1shoukr/swiftplay-backend/internal/database"
 	"github.com/1shoukr/swiftplay-backend/internal/handlers"
-	"github.com/1shoukr/swiftplay-backend/internal/jwt"
 	"github.com/1shoukr/swiftplay-backend/internal/middleware"
+	"github.com/1shoukr/swiftplay-backend/internal/rbac"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupUserRoutes(api *gin.RouterGroup, db *database.Database, jwtService *jwt.JWTService) {
-	userHandler := handlers.NewUserHandler(db)
+func SetupUserRoutes(api *gin.RouterGroup, deps *Dependencies) {
+	userHandler := handlers.NewUserHandler(deps.DB, deps.JWTService, deps.Mailer, deps.UserStates, deps.Games, deps.Config.Auth)
+	mobileJWT := deps.JWTService.ForAudience(deps.Config.JWT.MobileAudience)
 
 	users := api.Group("/users")
 	{
-		users.POST("/create", userHandler.CreateUser)
+		users.POST("/create", authLimiter(deps).Middleware(), userHandler.CreateUser)
 
-		users.GET("/profile", middleware.RequireUser(jwtService), getUserProfile)
-		users.GET("/admin-only", middleware.RequireAdmin(jwtService), adminOnlyEndpoint)
-		users.GET("/super-admin-only", middleware.RequireSuperAdmin(jwtService), superAdminOnlyEndpoint)
-		users.GET("/engineer-only", middleware.RequireEngineer(jwtService), engineerOnlyEndpoint)
+		users.GET("/me", middleware.RequirePermission(mobileJWT, deps.RBAC, rbac.PermProfileRead), userHandler.GetMe)
+		users.PATCH("/me", middleware.RequirePermission(mobileJWT, deps.RBAC, rbac.PermProfileWrite), userHandler.UpdateMe)
+		users.DELETE("/me", middleware.RequirePermission(mobileJWT, deps.RBAC, rbac.PermAccountManage), userHandler.DeleteMe)
+
+		users.GET("/profile", middleware.RequirePermission(mobileJWT, deps.RBAC, rbac.PermProfileRead), getUserProfile)
 	}
 }
 
@@ -43,63 +44,3 @@ func getUserProfile(c *gin.Context) {
 		},
 	})
 }
-
-func adminOnlyEndpoint(c *gin.Context) {
-	userID, email, role, exists := middleware.GetUserFromContext(c)
-	if !exists {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "User data not found in context",
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Admin access granted",
-		"user": gin.H{
-			"id":    userID,
-			"email": email,
-			"role":  role,
-		},
-		"data": "This is admin-only data",
-	})
-}
-
-func superAdminOnlyEndpoint(c *gin.Context) {
-	userID, email, role, exists := middleware.GetUserFromContext(c)
-	if !exists {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "User data not found in context",
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Super admin access granted",
-		"user": gin.H{
-			"id":    userID,
-			"email": email,
-			"role":  role,
-		},
-		"data": "This is super admin-only data",
-	})
-}
-
-func engineerOnlyEndpoint(c *gin.Context) {
-	userID, email, role, exists := middleware.GetUserFromContext(c)
-	if !exists {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "User data not found in context",
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Engineer access granted",
-		"user": gin.H{
-			"id":    userID,
-			"email": email,
-			"role":  role,
-		},
-		"data": "This is engineer-only data",
-	})
-}
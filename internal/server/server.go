@@ -1,13 +1,28 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
+	"github.com/1shoukr/swiftplay-backend/internal/accounts"
+	"github.com/1shoukr/swiftplay-backend/internal/audit"
+	"github.com/1shoukr/swiftplay-backend/internal/chat"
 	"github.com/1shoukr/swiftplay-backend/internal/config"
 	"github.com/1shoukr/swiftplay-backend/internal/database"
+	"github.com/1shoukr/swiftplay-backend/internal/games"
+	"github.com/1shoukr/swiftplay-backend/internal/jobs"
 	"github.com/1shoukr/swiftplay-backend/internal/jwt"
+	"github.com/1shoukr/swiftplay-backend/internal/mailer"
+	"github.com/1shoukr/swiftplay-backend/internal/matches"
+	"github.com/1shoukr/swiftplay-backend/internal/ratelimit"
+	"github.com/1shoukr/swiftplay-backend/internal/rbac"
+	"github.com/1shoukr/swiftplay-backend/internal/realtime"
+	"github.com/1shoukr/swiftplay-backend/internal/recommendations"
+	"github.com/1shoukr/swiftplay-backend/internal/revocation"
 	"github.com/1shoukr/swiftplay-backend/internal/server/routes"
+	"github.com/1shoukr/swiftplay-backend/internal/userstate"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 )
@@ -17,6 +32,9 @@ type Server struct {
 	DB         *database.Database
 	Config     *config.ServerConfig
 	JWTService *jwt.JWTService
+	jobs       *jobs.Runner
+	hub        *realtime.Hub
+	stream     *realtime.Stream
 }
 
 func NewServer() (*Server, error) {
@@ -40,23 +58,103 @@ func NewServer() (*Server, error) {
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
 	}
 
-	jwtService := jwt.NewJWTService(serverConfig.JWT)
+	revocationStore, err := revocation.NewStore(serverConfig.JWT.RevocationStore, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize token revocation store: %w", err)
+	}
+
+	jwtService, err := jwt.NewJWTService(serverConfig.JWT, revocationStore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize JWT service: %w", err)
+	}
+
+	rateLimitStore, err := ratelimit.NewStore(serverConfig.RateLimit.Store, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize rate limit store: %w", err)
+	}
+
+	mailService, err := mailer.New(serverConfig.Mailer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize mailer: %w", err)
+	}
+
+	policy, err := rbac.Load(serverConfig.Auth.RBACPolicyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load RBAC policy: %w", err)
+	}
+
+	catalog, err := games.Load(serverConfig.Matchmaking.GameCatalogPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load game catalog: %w", err)
+	}
+
+	hub := realtime.NewHub()
+	stream := realtime.NewStream(serverConfig.Realtime.StreamLogSize, serverConfig.Realtime.StreamLogRetention)
+	// Typing indicators and read receipts are only worth delivering live, so
+	// they stay out of the stream log and cannot push messages out of it
+	publisher := realtime.Publishers{
+		hub,
+		realtime.OnlyTypes(stream, realtime.EventMessageCreated, realtime.EventMatchCreated, realtime.EventMatchUpdated),
+	}
+
+	matchService := matches.NewService(db.GetDB(), publisher)
+	recommendationService := recommendations.NewService(db.GetDB(), catalog, serverConfig.Recommendations, serverConfig.Matchmaking.PassCooldown)
+	chatService := chat.NewService(db.GetDB(), publisher)
 
 	engine := gin.Default()
 
-	routes.SetupRoutes(engine, db, jwtService)
+	routes.SetupRoutes(engine, &routes.Dependencies{
+		DB:              db,
+		JWTService:      jwtService,
+		Mailer:          mailService,
+		RBAC:            policy,
+		UserStates:      userstate.NewCache(db, serverConfig.Auth.UserStateCacheTTL),
+		Games:           catalog,
+		Matches:         matchService,
+		Recommendations: recommendationService,
+		Hub:             hub,
+		Stream:          stream,
+		Chat:            chatService,
+		Audit:           audit.NewLogger(db.GetDB()),
+		RateLimits:      rateLimitStore,
+		Config:          serverConfig,
+	})
+
+	runner := jobs.NewRunner()
+	runner.Every("purge-deleted-accounts", serverConfig.Auth.PurgeInterval, func(ctx context.Context) error {
+		_, err := accounts.PurgeDeletedUsers(ctx, db.GetDB(), time.Now().Add(-serverConfig.Auth.DeletionGracePeriod))
+		return err
+	})
+	runner.Every("expire-pending-matches", serverConfig.Matchmaking.ExpiryInterval, func(ctx context.Context) error {
+		_, err := matchService.ExpirePending(ctx, time.Now().Add(-serverConfig.Matchmaking.PendingMatchTTL))
+		return err
+	})
+	runner.Every("precompute-recommendations", serverConfig.Recommendations.RefreshInterval, recommendationService.RefreshActive)
+	runner.Every("prune-event-logs", serverConfig.Realtime.StreamLogRetention, stream.Prune)
+	runner.Every("prune-rate-limits", serverConfig.RateLimit.PruneInterval, rateLimitStore.Prune)
 
 	server := &Server{
 		engine:     engine,
 		DB:         db,
 		Config:     serverConfig,
 		JWTService: jwtService,
+		jobs:       runner,
+		hub:        hub,
+		stream:     stream,
 	}
 
 	log.Printf("Server configured successfully - Port: %d, Gin Mode: %s",
 		serverConfig.Port, serverConfig.GinMode)
 	log.Printf("JWT configuration loaded - Token expiry: %v, Refresh expiry: %v",
 		serverConfig.JWT.Expiry, serverConfig.JWT.RefreshTokenExpiry)
+	if serverConfig.JWT.KeysDir != "" {
+		log.Printf("JWT access tokens signed with asymmetric keys from %s", serverConfig.JWT.KeysDir)
+	}
+	log.Printf("RBAC policy loaded - Roles: %v, Live user lookup: %v", policy.Roles(), serverConfig.Auth.LiveUserLookup)
+	log.Printf("Game catalog loaded - Games: %d", len(catalog.Games()))
+	log.Printf("Mailer configured - Driver: %s", serverConfig.Mailer.Driver)
+	log.Printf("Rate limits configured - Store: %s, Default: %d/min, Auth: %d/min, Refresh: %d/min, Messages: %d/min",
+		serverConfig.RateLimit.Store, serverConfig.RateLimit.Default, serverConfig.RateLimit.Auth, serverConfig.RateLimit.Refresh, serverConfig.RateLimit.Messages)
 
 	return server, nil
 }
@@ -66,6 +164,18 @@ func (s *Server) Run(addr string) error {
 }
 
 func (s *Server) Close() error {
+	if s.jobs != nil {
+		s.jobs.Close()
+	}
+	if s.stream != nil {
+		s.stream.Close()
+	}
+	if s.hub != nil {
+		s.hub.Close()
+	}
+	if s.JWTService != nil {
+		s.JWTService.Close()
+	}
 	if s.DB != nil {
 		return s.DB.Close()
 	}
@@ -0,0 +1,101 @@
+package userstate
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/1shoukr/swiftplay-backend/internal/database"
+	"github.com/1shoukr/swiftplay-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// State is the live account state authorization decisions are based on
+type State struct {
+	UserID    uint
+	AuthLevel string
+	Active    bool
+}
+
+// pruneThreshold is the number of cached users above which expired entries are swept
+const pruneThreshold = 10000
+
+type cacheEntry struct {
+	state     State
+	expiresAt time.Time
+}
+
+// Cache loads account state from the users table and keeps it in process for a
+// short TTL. Code that changes a user's role, deletes, suspends or bans them must
+// call Invalidate so this instance sees the change immediately; other instances
+// catch up when their entry expires.
+type Cache struct {
+	db  *gorm.DB
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[uint]cacheEntry
+}
+
+// NewCache creates an account state cache with the given TTL
+func NewCache(db *database.Database, ttl time.Duration) *Cache {
+	return &Cache{
+		db:      db.GetDB(),
+		ttl:     ttl,
+		entries: make(map[uint]cacheEntry),
+	}
+}
+
+// Load returns the current state of a user. Users that no longer exist, are
+// soft-deleted by either SoftDelete or DeletedAt, or are banned or suspended are
+// reported as inactive.
+func (c *Cache) Load(ctx context.Context, userID uint) (*State, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[userID]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		state := entry.state
+		return &state, nil
+	}
+
+	var users []models.User
+	if err := c.db.WithContext(ctx).Unscoped().
+		Select("user_id", "auth_level", "soft_delete", "deleted_at", "suspended_until", "banned_at").
+		Where("user_id = ?", userID).Limit(1).Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	state := State{UserID: userID}
+	if len(users) > 0 {
+		user := users[0]
+		state.AuthLevel = user.AuthLevel
+		state.Active = !user.SoftDelete && !user.DeletedAt.Valid && !user.IsBanned() && !user.IsSuspended(now)
+	}
+
+	c.mu.Lock()
+	if len(c.entries) >= pruneThreshold {
+		c.pruneLocked(now)
+	}
+	c.entries[userID] = cacheEntry{state: state, expiresAt: now.Add(c.ttl)}
+	c.mu.Unlock()
+
+	return &state, nil
+}
+
+// Invalidate drops the cached state for a user
+func (c *Cache) Invalidate(userID uint) {
+	c.mu.Lock()
+	delete(c.entries, userID)
+	c.mu.Unlock()
+}
+
+// pruneLocked removes expired entries; the caller must hold c.mu
+func (c *Cache) pruneLocked(now time.Time) {
+	for userID, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, userID)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package validation
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FieldErrors maps a request field, as a dotted JSON path, to the problem found with it
+type FieldErrors map[string]string
+
+// Add records a problem with a field, keeping the first problem reported for it
+func (e FieldErrors) Add(field, message string) {
+	if _, exists := e[field]; !exists {
+		e[field] = message
+	}
+}
+
+// HasErrors reports whether any field failed validation
+func (e FieldErrors) HasErrors() bool {
+	return len(e) > 0
+}
+
+// Respond writes the field errors as a 400 response
+func (e FieldErrors) Respond(c *gin.Context) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":  "Validation failed",
+		"fields": e,
+	})
+}
+
+// Error lets FieldErrors be returned through error-typed paths such as transactions
+func (e FieldErrors) Error() string {
+	return "validation failed"
+}